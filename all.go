@@ -0,0 +1,44 @@
+//go:build go1.23
+
+package json_select
+
+import (
+	"iter"
+)
+
+// All returns an iterator over the elements of the wrapped value, for use
+// with range:
+//		for key, val := range j.All() {
+//			...
+//		}
+// The keys and values are the same as those passed to the callback of Each:
+// an int index for each element of a []interface{}, and a string key for
+// each value of a map[string]interface{} in map order or of an *OrderedMap
+// in its key order. Unlike Each nothing is copied or returned up front, and
+// the iterator yields nothing if the value is not an array or object.
+func (j Selecter) All() iter.Seq2[interface{}, Selecter] {
+	return func(yield func(interface{}, Selecter) bool) {
+		switch v := j.V.(type) {
+		case []interface{}:
+			for i, elem := range v {
+				if !yield(i, j.wrap(elem)) {
+					return
+				}
+			}
+
+		case map[string]interface{}:
+			for k, elem := range v {
+				if !yield(k, j.wrap(elem)) {
+					return
+				}
+			}
+
+		case *OrderedMap:
+			for _, k := range v.keys {
+				if !yield(k, j.wrap(v.values[k])) {
+					return
+				}
+			}
+		}
+	}
+}
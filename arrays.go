@@ -0,0 +1,136 @@
+package json_select
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// SortBy is like SelectSlice but returns the elements stably sorted with
+// less. The selected array itself is not modified.
+func (j Selecter) SortBy(less func(a, b Selecter) bool, sels ...interface{}) ([]Selecter, error) {
+	slc, err := j.SelectSlice(sels...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(slc, func(a, b int) bool {
+		return less(slc[a], slc[b])
+	})
+
+	return slc, nil
+}
+
+// SortByField is like SortBy but sorts the elements by the value of field.
+// Numbers are compared numerically and strings lexicographically, with all
+// the numbers ordered before all the strings. Elements for which field is
+// missing, or is any other kind of value, are ordered last and kept in
+// their original order.
+func (j Selecter) SortByField(field string, sels ...interface{}) ([]Selecter, error) {
+	return j.SortBy(func(a, b Selecter) bool {
+		av, _ := a.Raw(field)
+		bv, _ := b.Raw(field)
+
+		ar, br := sortRank(av), sortRank(bv)
+		if ar != br {
+			return ar < br
+		}
+
+		switch ar {
+		case 0:
+			af, _ := toFloat(av)
+			bf, _ := toFloat(bv)
+			return af < bf
+		case 1:
+			return av.(string) < bv.(string)
+		default:
+			return false
+		}
+	}, sels...)
+}
+
+// sortRank orders the kinds of values for SortByField
+func sortRank(v interface{}) int {
+	switch kindOf(v) {
+	case KindNumber:
+		return 0
+	case KindString:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// GroupBy is like SelectSlice but groups the elements, which must be
+// objects, by the string value of field. The elements of each group are in
+// their original order. An error identifying the element is returned if
+// field can't be selected from an element as a string, see GroupByOr to
+// group those elements instead.
+func (j Selecter) GroupBy(field string, sels ...interface{}) (map[string][]Selecter, error) {
+	return j.groupBy(field, nil, sels)
+}
+
+// GroupByOr is like GroupBy but the elements for which field is missing or
+// not a string, or which are not objects, are grouped under bucket.
+func (j Selecter) GroupByOr(field, bucket string, sels ...interface{}) (map[string][]Selecter, error) {
+	return j.groupBy(field, &bucket, sels)
+}
+
+func (j Selecter) groupBy(field string, bucket *string, sels []interface{}) (map[string][]Selecter, error) {
+	slc, err := j.SelectSlice(sels...)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := map[string][]Selecter{}
+	for i, v := range slc {
+		k, err := v.SelectString(field)
+		if err != nil {
+			if bucket == nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+
+			k = *bucket
+		}
+
+		ret[k] = append(ret[k], v)
+	}
+
+	return ret, nil
+}
+
+// ErrEmptyArray is returned by First and Last when the array is empty
+var ErrEmptyArray = errors.New("array is empty")
+
+// First is like Select but returns the first element of the selection, which
+// must be a []interface{}. ErrEmptyArray is returned if it has no elements.
+func (j Selecter) First(sels ...interface{}) (Selecter, error) {
+	return j.arrayEnd(sels, true)
+}
+
+// Last is like First but returns the last element of the selection
+func (j Selecter) Last(sels ...interface{}) (Selecter, error) {
+	return j.arrayEnd(sels, false)
+}
+
+func (j Selecter) arrayEnd(sels []interface{}, first bool) (Selecter, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return j.wrap(nil), err
+	}
+
+	arr, ok := v.([]interface{})
+	if !ok {
+		return j.wrap(nil), coercionError(v, "[]interface{}", nil)
+	}
+
+	if len(arr) == 0 {
+		return j.wrap(nil), fmt.Errorf("%w: %v", ErrEmptyArray, sels)
+	}
+
+	if first {
+		return j.wrap(arr[0]), nil
+	}
+
+	return j.wrap(arr[len(arr)-1]), nil
+}
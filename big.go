@@ -0,0 +1,102 @@
+package json_select
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// SelectBigFloat is like SelectFloat but converts the selection into a
+// *big.Float, for numbers which can't be represented by a float64 without
+// losing precision, such as monetary amounts. Only numbers decoded with
+// json.Decoder.UseNumber, or strings, keep their digits: they're parsed with
+// enough precision for every digit to be significant. A float64 has
+// already been rounded when it was decoded and is converted exactly. Note
+// that a big.Float is still binary, so a decimal fraction such as 0.1 is
+// rounded in its last bit, see SelectJSON for the exact text.
+func (j Selecter) SelectBigFloat(sels ...interface{}) (*big.Float, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		return nil, fmt.Errorf("%w: %v", ErrNilValue, sels)
+	}
+
+	switch vv := v.(type) {
+	case json.Number:
+		return parseBigFloat(vv, string(vv))
+	case string:
+		return parseBigFloat(vv, vv)
+	case int:
+		return new(big.Float).SetInt64(int64(vv)), nil
+	case float64:
+		if math.IsNaN(vv) {
+			return nil, coercionError(v, "*big.Float", nil)
+		}
+
+		return big.NewFloat(vv), nil
+	default:
+		return nil, coercionError(v, "*big.Float", nil)
+	}
+}
+
+// parseBigFloat parses str, the digits of v, with a precision of at least
+// 64 bits and enough for every decimal digit
+func parseBigFloat(v interface{}, str string) (*big.Float, error) {
+	prec := uint(len(str)) * 4
+	if prec < 64 {
+		prec = 64
+	}
+
+	f, _, err := big.ParseFloat(str, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, coercionError(v, "*big.Float", err)
+	}
+
+	return f, nil
+}
+
+// SelectBigInt is like SelectInt64 but converts the selection into a
+// *big.Int, for integers such as IDs which are too large for an int64 or to
+// be represented exactly by a float64. As with SelectBigFloat only numbers
+// decoded with json.Decoder.UseNumber, or strings, keep all of their
+// digits. A number with a fractional part is an error rather than being
+// truncated, while an integral one such as 1e3 is converted.
+func (j Selecter) SelectBigInt(sels ...interface{}) (*big.Int, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		return nil, fmt.Errorf("%w: %v", ErrNilValue, sels)
+	}
+
+	var f *big.Float
+	switch vv := v.(type) {
+	case json.Number:
+		if i, ok := new(big.Int).SetString(string(vv), 10); ok {
+			return i, nil
+		}
+
+		f, err = parseBigFloat(vv, string(vv))
+	case string:
+		if i, ok := new(big.Int).SetString(vv, 10); ok {
+			return i, nil
+		}
+
+		f, err = parseBigFloat(vv, vv)
+	default:
+		f, err = j.wrap(v).SelectBigFloat()
+	}
+
+	if err != nil || !f.IsInt() || f.IsInf() {
+		return nil, coercionError(v, "*big.Int", nil)
+	}
+
+	i, _ := f.Int(nil)
+	return i, nil
+}
@@ -0,0 +1,178 @@
+package json_select
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Equal reports whether the wrapped values are structurally equal with JSON
+// semantics: objects are compared without regard to key order, arrays are
+// compared element by element in order, and numbers are compared by value
+// after converting them to float64. Any Go numeric type, or json.Number, is
+// treated as a number, so an int set with Set is equal to the same float64
+// decoded by json.Unmarshal, although ints too large to be exactly
+// represented by a float64 may compare equal to their neighbours. An
+// *OrderedMap is an object like any other, so it's equal to a
+// map[string]interface{} with the same keys and values. Values of other
+// types are compared with reflect.DeepEqual.
+func (j Selecter) Equal(other Selecter) bool {
+	return equal(j.V, other.V)
+}
+
+// EqualJSON is like Equal but compares against the JSON document in data,
+// an error is returned if data can't be unmarshaled.
+func (j Selecter) EqualJSON(data []byte) (bool, error) {
+	var v interface{}
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return false, err
+	}
+
+	return equal(j.V, v), nil
+}
+
+// Contains is like Select but reports whether the selection, which must be
+// a []interface{}, has an element which is Equal to value. value may also
+// be a Selecter. Only arrays are searched, an error is returned for any
+// other selection, including an object.
+func (j Selecter) Contains(value interface{}, sels ...interface{}) (bool, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return false, err
+	}
+
+	if s, ok := value.(Selecter); ok {
+		value = s.V
+	}
+
+	arr, ok := v.([]interface{})
+	if !ok {
+		return false, coercionError(v, "[]interface{}", nil)
+	}
+
+	for _, elem := range arr {
+		if equal(elem, value) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func equal(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(*OrderedMap); ok {
+			return equalOrdered(bv, av)
+		}
+
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		for k, v := range av {
+			w, ok := bv[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+
+		return true
+
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+
+		return true
+
+	case *OrderedMap:
+		return equalOrdered(av, b)
+	}
+
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	if aok || bok {
+		return aok && bok && af == bf
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// equalOrdered is equal for an *OrderedMap, which is equal to another
+// object with the same keys and values regardless of their order
+func equalOrdered(a *OrderedMap, b interface{}) bool {
+	var get func(string) (interface{}, bool)
+	n := 0
+
+	switch bv := b.(type) {
+	case *OrderedMap:
+		get, n = bv.Get, bv.Len()
+	case map[string]interface{}:
+		get = func(k string) (interface{}, bool) {
+			v, ok := bv[k]
+			return v, ok
+		}
+
+		n = len(bv)
+	default:
+		return false
+	}
+
+	if a.Len() != n {
+		return false
+	}
+
+	for _, k := range a.keys {
+		w, ok := get(k)
+		if !ok || !equal(a.values[k], w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// toFloat converts any of the Go numeric types, or a json.Number, into a
+// float64. A json.Number out of the range of a float64 is not converted.
+func toFloat(v interface{}) (float64, bool) {
+	switch vv := v.(type) {
+	case float64:
+		return vv, true
+	case float32:
+		return float64(vv), true
+	case int:
+		return float64(vv), true
+	case int8:
+		return float64(vv), true
+	case int16:
+		return float64(vv), true
+	case int32:
+		return float64(vv), true
+	case int64:
+		return float64(vv), true
+	case uint:
+		return float64(vv), true
+	case uint8:
+		return float64(vv), true
+	case uint16:
+		return float64(vv), true
+	case uint32:
+		return float64(vv), true
+	case uint64:
+		return float64(vv), true
+	case json.Number:
+		f, err := vv.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,71 @@
+package json_select
+
+import (
+	"testing"
+)
+
+func TestEqualOrdered(t *testing.T) {
+	doc := `{"a":1,"b":[{"c":true,"d":null}],"e":{}}`
+	reordered := `{"e":{},"b":[{"d":null,"c":true}],"a":1.0}`
+
+	plain, err := FromJSON([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ordered, err := DecodeOrdered([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orderedReordered, err := DecodeOrdered([]byte(reordered))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ordered.Equal(plain) || !plain.Equal(ordered) {
+		t.Error("ordered document is not Equal to the plain document")
+	}
+
+	if !ordered.Equal(orderedReordered) {
+		t.Error("ordered documents with different key orders are not Equal")
+	}
+
+	ok, err := ordered.EqualJSON([]byte(reordered))
+	if err != nil || !ok {
+		t.Errorf("EqualJSON = %v, %v, want true", ok, err)
+	}
+
+	other, err := DecodeOrdered([]byte(`{"a":1,"b":[{"c":false,"d":null}],"e":{}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ordered.Equal(other) || plain.Equal(other) || other.Equal(plain) {
+		t.Error("documents with different values are Equal")
+	}
+
+	missing, err := DecodeOrdered([]byte(`{"a":1,"b":[{"c":true,"d":null}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ordered.Equal(missing) || missing.Equal(plain) || plain.Equal(missing) {
+		t.Error("documents with different keys are Equal")
+	}
+
+	found, err := ordered.Contains(map[string]interface{}{"d": nil, "c": true}, "b")
+	if err != nil || !found {
+		t.Errorf("Contains = %v, %v, want true", found, err)
+	}
+
+	q, err := CompileJSONPath(`$.b[?(@.c == true)]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err := q.Nodes(ordered)
+	if err != nil || len(nodes) != 1 || !nodes[0].Equal(Selecter{V: map[string]interface{}{"c": true, "d": nil}}) {
+		t.Errorf("Nodes = %v, %v", nodes, err)
+	}
+}
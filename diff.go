@@ -0,0 +1,158 @@
+package json_select
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Op is the operation of a Change or of an Operation of a JSON Patch
+type Op string
+
+const (
+	OpAdd     Op = "add"
+	OpRemove  Op = "remove"
+	OpReplace Op = "replace"
+	OpMove    Op = "move"
+	OpCopy    Op = "copy"
+	OpTest    Op = "test"
+)
+
+// Change is a difference between two documents found by Diff. Path is the
+// string keys and int indices of the changed value, the same as a path
+// passed to a Walk callback. Old is the value in the first document and New
+// the value in the second, Old is nil for an OpAdd and New is nil for an
+// OpRemove.
+type Change struct {
+	Op   Op
+	Path []interface{}
+	Old  interface{}
+	New  interface{}
+}
+
+// Pointer returns the path of the change as an RFC 6901 JSON Pointer
+func (c Change) Pointer() string {
+	return formatPointer(c.Path)
+}
+
+// Operation returns the JSON Patch operation which makes the change, see
+// ApplyPatch
+func (c Change) Operation() Operation {
+	return Operation{Op: c.Op, Path: c.Pointer(), Value: c.New}
+}
+
+// Diff returns the changes from the wrapped value to other, see the Diff
+// function.
+func (j Selecter) Diff(other Selecter) ([]Change, error) {
+	return Diff(j.V, other.V)
+}
+
+// Diff returns the changes which turn a into b, in the style of an RFC 6902
+// JSON Patch. Objects are compared key by key, in sorted order (or key order
+// for an *OrderedMap), and arrays element by element, with the elements
+// past the end of the shorter array added or removed. The removals from an
+// array are listed from the last element backwards, so that the changes can
+// be applied in order. Any other values which are not Equal are replaced.
+// An error is returned if a or b contains a value which is not a JSON type.
+func Diff(a, b interface{}) ([]Change, error) {
+	var changes []Change
+	err := diff([]interface{}{}, a, b, &changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func diff(path []interface{}, a, b interface{}, changes *[]Change) error {
+	ka, kb := kindOf(a), kindOf(b)
+	if ka == KindInvalid || kb == KindInvalid {
+		v := a
+		if ka != KindInvalid {
+			v = b
+		}
+
+		msg := fmt.Sprintf("cannot diff %v (%T)", v, v)
+		if len(path) > 0 {
+			msg += " at path " + formatPath(path)
+		}
+
+		return errors.New(msg)
+	}
+
+	// the path is copied since it's shared with the sibling values
+	change := func(op Op, path []interface{}, oldv, newv interface{}) {
+		*changes = append(*changes, Change{
+			Op:   op,
+			Path: append([]interface{}{}, path...),
+			Old:  oldv,
+			New:  newv,
+		})
+	}
+
+	switch {
+	case ka == KindObject && kb == KindObject:
+		akeys, avals := objectEntries(a)
+		bkeys, bvals := objectEntries(b)
+
+		for _, k := range akeys {
+			bv, ok := bvals[k]
+			if !ok {
+				change(OpRemove, append(path, k), avals[k], nil)
+				continue
+			}
+
+			err := diff(append(path, k), avals[k], bv, changes)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, k := range bkeys {
+			if _, ok := avals[k]; !ok {
+				change(OpAdd, append(path, k), nil, bvals[k])
+			}
+		}
+
+	case ka == KindArray && kb == KindArray:
+		av, bv := a.([]interface{}), b.([]interface{})
+
+		n := len(av)
+		if len(bv) < n {
+			n = len(bv)
+		}
+
+		for i := 0; i < n; i++ {
+			err := diff(append(path, i), av[i], bv[i], changes)
+			if err != nil {
+				return err
+			}
+		}
+
+		for i := len(av) - 1; i >= n; i-- {
+			change(OpRemove, append(path, i), av[i], nil)
+		}
+
+		for i := n; i < len(bv); i++ {
+			change(OpAdd, append(path, i), nil, bv[i])
+		}
+
+	default:
+		if !equal(a, b) {
+			change(OpReplace, path, a, b)
+		}
+	}
+
+	return nil
+}
+
+// objectEntries returns the keys of obj, which must be a
+// map[string]interface{} or *OrderedMap, in sorted or key order, and its
+// values
+func objectEntries(obj interface{}) ([]string, map[string]interface{}) {
+	if om, ok := obj.(*OrderedMap); ok {
+		return om.keys, om.values
+	}
+
+	m := obj.(map[string]interface{})
+	return sortedKeys(m), m
+}
@@ -0,0 +1,166 @@
+package json_select
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// FromJSON returns a Selecter for the JSON document in data, which is
+// unmarshaled the same as by json.Unmarshal into an interface{}.
+func FromJSON(data []byte) (Selecter, error) {
+	var j Selecter
+	err := json.Unmarshal(data, &j.V)
+	if err != nil {
+		return Selecter{}, err
+	}
+
+	return j, nil
+}
+
+// FromReader is like FromJSON but reads the document from r. It's an error
+// for r to hold anything other than whitespace after the document.
+func FromReader(r io.Reader) (Selecter, error) {
+	dec := json.NewDecoder(r)
+
+	var j Selecter
+	err := dec.Decode(&j.V)
+	if err != nil {
+		return Selecter{}, err
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return Selecter{}, fmt.Errorf("invalid data after top-level value")
+	}
+
+	return j, nil
+}
+
+// MarshalJSON implements json.Marshaler by marshaling the wrapped value, so
+// a Selecter marshals the same as its V.
+func (j Selecter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.V)
+}
+
+// UnmarshalJSON implements json.Unmarshaler by unmarshaling into the wrapped
+// value, so a Selecter field in a struct decodes into a generic value.
+func (j *Selecter) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &j.V)
+}
+
+// JSON returns the JSON encoding of the wrapped value
+func (j Selecter) JSON() ([]byte, error) {
+	return j.MarshalJSON()
+}
+
+// SelectJSON is like Select but returns the JSON encoding of the selection,
+// for forwarding a part of a document without encoding the rest of it. A
+// selection which is present but null is encoded as null, not an error.
+func (j Selecter) SelectJSON(sels ...interface{}) ([]byte, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(v)
+}
+
+// EncodeTo is like SelectJSON but writes the encoding of the selection to w
+// with a json.Encoder, followed by a newline, for example to write it as
+// an HTTP response. As with json.Encoder, characters such as < and > are
+// escaped in strings. See EncodeIndentTo for indented output.
+func (j Selecter) EncodeTo(w io.Writer, sels ...interface{}) error {
+	return j.EncodeIndentTo(w, "", "", sels...)
+}
+
+// EncodeIndentTo is like EncodeTo but indents the encoding as with
+// json.Encoder.SetIndent, so each element of an object or array begins on a
+// new line starting with prefix followed by copies of indent.
+func (j Selecter) EncodeIndentTo(w io.Writer, prefix, indent string, sels ...interface{}) error {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent(prefix, indent)
+	return enc.Encode(v)
+}
+
+// String implements fmt.Stringer by returning the compact JSON encoding of
+// the wrapped value, or its %v formatting if it can't be marshaled.
+func (j Selecter) String() string {
+	data, err := json.Marshal(j.V)
+	if err != nil {
+		return fmt.Sprintf("%v", j.V)
+	}
+
+	return string(data)
+}
+
+// Format implements fmt.Formatter. The %v and %s verbs print the compact
+// JSON encoding of the wrapped value as String does, %+v prints it indented,
+// %q prints it as a quoted string, and %#v prints the Go representation of
+// the Selecter. If the value can't be marshaled the JSON forms fall back to
+// the %v formatting of the wrapped value. Any other verb is applied to the
+// wrapped value.
+func (j Selecter) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprintf(f, "json_select.Selecter{V:%#v}", j.V)
+	case verb == 'v' && f.Flag('+'):
+		data, err := json.MarshalIndent(j.V, "", "\t")
+		if err != nil {
+			fmt.Fprintf(f, "%v", j.V)
+			return
+		}
+
+		f.Write(data)
+	case verb == 'v', verb == 's':
+		io.WriteString(f, j.String())
+	case verb == 'q':
+		fmt.Fprintf(f, "%q", j.String())
+	default:
+		fmt.Fprintf(f, formatDirective(f, verb), j.V)
+	}
+}
+
+// formatDirective rebuilds the directive, including its flags, width, and
+// precision, which Format was called for
+func formatDirective(f fmt.State, verb rune) string {
+	dir := "%"
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			dir += string(flag)
+		}
+	}
+
+	if w, ok := f.Width(); ok {
+		dir += strconv.Itoa(w)
+	}
+
+	if p, ok := f.Precision(); ok {
+		dir += "." + strconv.Itoa(p)
+	}
+
+	return dir + string(verb)
+}
+
+// Decode is like Select but decodes the selection into out, which must be a
+// pointer, as json.Unmarshal would. The selection is marshaled back into
+// JSON and then unmarshaled, so json struct tags and custom unmarshalers
+// are supported.
+func (j Selecter) Decode(out interface{}, sels ...interface{}) error {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, out)
+}
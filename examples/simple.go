@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
 
@@ -19,9 +18,7 @@ const data = `
 `
 
 func main() {
-	var v json_select.Selecter
-
-	err := json.Unmarshal([]byte(data), &v.V)
+	v, err := json_select.FromJSON([]byte(data))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,33 @@
+package json_select
+
+import (
+	"os"
+)
+
+// SelectStringExpand is like SelectString but replaces ${var} or $var in the
+// string with the value of the environment variable, as with os.ExpandEnv, so
+// a value of "${HOME}/data" is resolved when it's read. An undefined variable
+// is replaced with an empty string. Unlike os.ExpandEnv, "$$" is an escape
+// for a literal $, e.g. "price: $$5" is "price: $5".
+func (j Selecter) SelectStringExpand(sels ...interface{}) (string, error) {
+	return j.SelectStringExpandFunc(func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+
+		return os.Getenv(name)
+	}, sels...)
+}
+
+// SelectStringExpandFunc is like SelectStringExpand but replaces the
+// variables with the result of mapping rather than the environment, see
+// os.Expand. mapping is called with "$" for "$$", it should return "$" for
+// that to be a literal $.
+func (j Selecter) SelectStringExpandFunc(mapping func(string) string, sels ...interface{}) (string, error) {
+	str, err := j.SelectString(sels...)
+	if err != nil {
+		return "", err
+	}
+
+	return os.Expand(str, mapping), nil
+}
@@ -0,0 +1,158 @@
+package json_select
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Flatten returns the leaf values of the wrapped value keyed by their path
+// from the wrapped value, with the keys and indices of the path joined by
+// sep. For example, flattening {"menu": [{"name": "x"}]} with a sep of "."
+// gives {"menu.0.name": "x"}. The leaves are every value which is not an
+// object or array, as well as empty objects and arrays so that they are
+// kept by Unflatten.
+//
+// An error is returned if sep is empty, if the wrapped value is not an
+// object or array, or if an object key contains sep, since the key could
+// not be split back apart by Unflatten.
+func (j Selecter) Flatten(sep string) (map[string]interface{}, error) {
+	if sep == "" {
+		return nil, fmt.Errorf("flatten separator cannot be empty")
+	}
+
+	switch kindOf(j.V) {
+	case KindObject, KindArray:
+	default:
+		return nil, fmt.Errorf("cannot flatten %v (%T)", j.V, j.V)
+	}
+
+	ret := map[string]interface{}{}
+	err := j.Walk(func(path []interface{}, val Selecter) error {
+		if len(path) == 0 {
+			return nil
+		}
+
+		switch kindOf(val.V) {
+		case KindObject, KindArray:
+			if n, _ := val.Len(); n > 0 {
+				return nil
+			}
+		}
+
+		parts := make([]string, len(path))
+		for i, p := range path {
+			switch p := p.(type) {
+			case string:
+				if strings.Contains(p, sep) {
+					return fmt.Errorf("key %q contains separator %q at path %s",
+						p, sep, formatPath(path[:i+1]))
+				}
+
+				parts[i] = p
+			case int:
+				parts[i] = strconv.Itoa(p)
+			}
+		}
+
+		ret[strings.Join(parts, sep)] = val.V
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// Unflatten is the inverse of Flatten, it splits each key of flat by sep and
+// sets the value at the resulting path in a new document. An object whose
+// keys are exactly the indices 0 to n-1 becomes an array, so an object such
+// as {"0": "x"} does not survive a round trip through Flatten. An error is
+// returned if sep is empty or if a key is a prefix of another key, so that
+// it would be both a leaf and an object.
+func Unflatten(flat map[string]interface{}, sep string) (Selecter, error) {
+	if sep == "" {
+		return Selecter{}, fmt.Errorf("unflatten separator cannot be empty")
+	}
+
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	root := flatNode{}
+	for _, k := range keys {
+		parts := strings.Split(k, sep)
+
+		node := root
+		for i, p := range parts[:len(parts)-1] {
+			child, ok := node[p]
+			if !ok {
+				child = flatNode{}
+				node[p] = child
+			}
+
+			next, ok := child.(flatNode)
+			if !ok {
+				return Selecter{}, fmt.Errorf("key %q conflicts with key %q",
+					k, strings.Join(parts[:i+1], sep))
+			}
+
+			node = next
+		}
+
+		last := parts[len(parts)-1]
+		if _, ok := node[last]; ok {
+			return Selecter{}, fmt.Errorf("key %q conflicts with a longer key", k)
+		}
+
+		node[last] = flat[k]
+	}
+
+	return Selecter{V: root.value()}, nil
+}
+
+// flatNode is an object built by Unflatten, it's a distinct type so that
+// it's not confused with an empty map[string]interface{} from flat.
+type flatNode map[string]interface{}
+
+// value converts n and the nodes nested in it to objects and arrays
+func (n flatNode) value() interface{} {
+	isArr := len(n) > 0
+	for k := range n {
+		idx, err := strconv.Atoi(k)
+		if err != nil || idx < 0 || idx >= len(n) || strconv.Itoa(idx) != k {
+			isArr = false
+			break
+		}
+	}
+
+	if isArr {
+		ret := make([]interface{}, len(n))
+		for k, v := range n {
+			idx, _ := strconv.Atoi(k)
+			ret[idx] = flatValue(v)
+		}
+
+		return ret
+	}
+
+	ret := make(map[string]interface{}, len(n))
+	for k, v := range n {
+		ret[k] = flatValue(v)
+	}
+
+	return ret
+}
+
+func flatValue(v interface{}) interface{} {
+	if n, ok := v.(flatNode); ok {
+		return n.value()
+	}
+
+	return v
+}
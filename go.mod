@@ -1,3 +1,3 @@
 module github.com/ear7h/json-select
 
-go 1.16
+go 1.18
@@ -0,0 +1,305 @@
+package json_select
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Each calls fn for every element of the wrapped value. For a []interface{}
+// the key is the int index of the element, and for a map[string]interface{}
+// the key is the string key of the value. Maps are iterated in Go's
+// (undefined) map order, an *OrderedMap in its key order. If fn returns an
+// error the iteration stops and the error is returned. An error is also
+// returned if the value is not an array or object.
+func (j Selecter) Each(fn func(key interface{}, val Selecter) error) error {
+	switch v := j.V.(type) {
+	case []interface{}:
+		for i, elem := range v {
+			err := fn(i, j.wrap(elem))
+			if err != nil {
+				return err
+			}
+		}
+
+	case map[string]interface{}:
+		for k, elem := range v {
+			err := fn(k, j.wrap(elem))
+			if err != nil {
+				return err
+			}
+		}
+
+	case *OrderedMap:
+		for _, k := range v.keys {
+			err := fn(k, j.wrap(v.values[k]))
+			if err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("cannot iterate over %v (%T)", j.V, j.V)
+	}
+
+	return nil
+}
+
+// EachPath is like Each but fn is passed the path of the element, which is
+// path, the path of the wrapped value from the root of the document, with
+// the key of the element appended. Nested calls can pass on the path they're
+// given so that errors are reported with the full context:
+//		err := doc.Select("menu").EachPath([]interface{}{"menu"},
+//			func(path []interface{}, item Selecter) error {
+//				_, err := item.SelectFloat("price")
+//				if err != nil {
+//					return fmt.Errorf("element %v: %w", path, err)
+//				}
+//				return nil
+//			})
+// Each call to fn is passed a new slice, so it may be retained, and path
+// itself is not modified.
+func (j Selecter) EachPath(path []interface{}, fn func(path []interface{}, val Selecter) error) error {
+	return j.Each(func(key interface{}, val Selecter) error {
+		p := make([]interface{}, len(path), len(path)+1)
+		copy(p, path)
+		return fn(append(p, key), val)
+	})
+}
+
+// SkipChildren is returned by a Walk callback to skip walking the values
+// nested inside of current value. It is not returned by Walk.
+var SkipChildren = errors.New("skip children")
+
+// Walk does a depth first traversal of the wrapped value, calling fn for
+// the value itself (with an empty path) and then for every value of every
+// object and element of every array nested inside it. The path holds the
+// string keys and int indices leading to the value from the wrapped value,
+// the same as would be passed to Select. Arrays are walked in order and
+// object keys in sorted order, or key order for an *OrderedMap. The path is
+// reused between calls so it must be copied if fn retains it. With the
+// MaxDepth option, reaching a value nested too deeply stops the walk with an
+// ErrMaxDepth.
+//
+// If fn returns SkipChildren the values nested in the current value are
+// skipped, any other error stops the walk and is returned.
+func (j Selecter) Walk(fn func(path []interface{}, val Selecter) error) error {
+	return j.WalkContext(context.Background(), fn)
+}
+
+// WalkContext is like Walk but stops and returns ctx.Err() if ctx is done
+// before the walk is finished. The context is checked periodically rather
+// than before every value.
+func (j Selecter) WalkContext(ctx context.Context, fn func(path []interface{}, val Selecter) error) error {
+	c := &ctxCheck{ctx: ctx}
+
+	err := j.walk(c, j.V, []interface{}{}, fn)
+	if err == SkipChildren {
+		return nil
+	}
+
+	return err
+}
+
+// Count walks the wrapped value as Walk does and returns the number of
+// values, including the wrapped value itself and every object and array,
+// for which pred returns true. See CountLeaves to only count the values
+// which are not objects or arrays.
+func (j Selecter) Count(pred func(Selecter) bool) (int, error) {
+	return j.count(pred, false)
+}
+
+// CountLeaves is like Count but only counts the values which are not
+// objects or arrays, pred is not called for the others.
+func (j Selecter) CountLeaves(pred func(Selecter) bool) (int, error) {
+	return j.count(pred, true)
+}
+
+func (j Selecter) count(pred func(Selecter) bool, leaves bool) (int, error) {
+	n := 0
+	err := j.Walk(func(_ []interface{}, val Selecter) error {
+		if leaves {
+			switch kindOf(val.V) {
+			case KindObject, KindArray:
+				return nil
+			}
+		}
+
+		if pred(val) {
+			n++
+		}
+
+		return nil
+	})
+
+	return n, err
+}
+
+func (j Selecter) walk(c *ctxCheck, obj interface{}, path []interface{}, fn func([]interface{}, Selecter) error) error {
+	err := c.check()
+	if err != nil {
+		return err
+	}
+
+	err = j.opts.checkDepth(len(path))
+	if err != nil {
+		return fmt.Errorf("%w at path %s", err, formatPath(path))
+	}
+
+	err = fn(path, j.wrap(obj))
+	if err != nil {
+		return err
+	}
+
+	switch objv := obj.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(objv) {
+			err := j.walk(c, objv[k], append(path, k), fn)
+			if err != nil && err != SkipChildren {
+				return err
+			}
+		}
+
+	case *OrderedMap:
+		for _, k := range objv.keys {
+			err := j.walk(c, objv.values[k], append(path, k), fn)
+			if err != nil && err != SkipChildren {
+				return err
+			}
+		}
+
+	case []interface{}:
+		for i, v := range objv {
+			err := j.walk(c, v, append(path, i), fn)
+			if err != nil && err != SkipChildren {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ctxCheckEvery is how many calls to ctxCheck.check there are between
+// checks of the context, it must be a power of 2
+const ctxCheckEvery = 256
+
+// ctxCheck periodically checks a context for cancellation during a
+// traversal. A nil *ctxCheck never reports an error.
+type ctxCheck struct {
+	ctx context.Context
+	n   int
+}
+
+func (c *ctxCheck) check() error {
+	if c == nil {
+		return nil
+	}
+
+	c.n++
+	if c.n&(ctxCheckEvery-1) != 1 {
+		return nil
+	}
+
+	return c.ctx.Err()
+}
+
+// MapLeaves returns a Selecter for a copy of the wrapped value in which
+// every value which is not an object or array, including null, is replaced
+// by the result of fn. The leaves are visited in the same order and with the
+// same path as Walk, and the path is reused in the same way. The objects and
+// arrays are rebuilt with the same keys and elements, an *OrderedMap keeping
+// its key order, so the wrapped value itself is not modified. For example, to
+// mask every string which looks like a card number:
+//		masked := j.MapLeaves(func(_ []interface{}, v interface{}) interface{} {
+//			if s, ok := v.(string); ok && cardNumber.MatchString(s) {
+//				return "****"
+//			}
+//			return v
+//		})
+func (j Selecter) MapLeaves(fn func(path []interface{}, v interface{}) interface{}) Selecter {
+	return j.wrap(mapLeaves(j.V, []interface{}{}, fn))
+}
+
+func mapLeaves(obj interface{}, path []interface{}, fn func([]interface{}, interface{}) interface{}) interface{} {
+	switch objv := obj.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(objv))
+		for _, k := range sortedKeys(objv) {
+			ret[k] = mapLeaves(objv[k], append(path, k), fn)
+		}
+
+		return ret
+
+	case *OrderedMap:
+		ret := NewOrderedMap()
+		for _, k := range objv.keys {
+			ret.Set(k, mapLeaves(objv.values[k], append(path, k), fn))
+		}
+
+		return ret
+
+	case []interface{}:
+		ret := make([]interface{}, len(objv))
+		for i, v := range objv {
+			ret[i] = mapLeaves(v, append(path, i), fn)
+		}
+
+		return ret
+
+	default:
+		return fn(path, obj)
+	}
+}
+
+// NormalizeNumbers returns a Selecter for a copy of the wrapped value in
+// which every number, of any of the Go numeric types or a json.Number, is a
+// float64, as when a document is decoded by json.Unmarshal. It's for values
+// which were added with Set or built by hand, so that code switching on the
+// type of a number only needs to handle one. Integers which a float64 can't
+// represent exactly lose precision, see NormalizeNumbersJSON to avoid that.
+func (j Selecter) NormalizeNumbers() Selecter {
+	return j.MapLeaves(func(_ []interface{}, v interface{}) interface{} {
+		if f, ok := v.(float32); ok {
+			// keep the digits a float32 is marshaled with, rather than
+			// those of its exact value
+			f64, _ := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'g', -1, 32), 64)
+			return f64
+		}
+
+		if f, ok := toFloat(v); ok {
+			return f
+		}
+
+		return v
+	})
+}
+
+// NormalizeNumbersJSON is like NormalizeNumbers but every number becomes a
+// json.Number, as when a document is decoded with json.Decoder.UseNumber.
+// Integers keep all of their digits, and a float64 has the digits it would
+// be marshaled with, so the encoding of the document doesn't change.
+func (j Selecter) NormalizeNumbersJSON() Selecter {
+	return j.MapLeaves(func(_ []interface{}, v interface{}) interface{} {
+		if kindOf(v) != KindNumber {
+			return v
+		}
+
+		switch vv := v.(type) {
+		case json.Number:
+			return vv
+		case float32, float64:
+			data, err := json.Marshal(vv)
+			if err != nil {
+				// NaN and infinities aren't JSON numbers
+				return v
+			}
+
+			return json.Number(data)
+		default:
+			return json.Number(fmt.Sprint(vv))
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package json_select
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// deepDoc is a document nested n levels deep, alternating between objects
+// and arrays, {"x":[{"x":[...]}]}, decoded from JSON
+func deepDoc(t *testing.T, n int) interface{} {
+	t.Helper()
+
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			sb.WriteString(`{"x":`)
+		} else {
+			sb.WriteString(`[`)
+		}
+	}
+
+	sb.WriteString(`1`)
+	for i := n - 1; i >= 0; i-- {
+		if i%2 == 0 {
+			sb.WriteString(`}`)
+		} else {
+			sb.WriteString(`]`)
+		}
+	}
+
+	j, err := FromJSON([]byte(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return j.V
+}
+
+func TestMaxDepthDeepDocument(t *testing.T) {
+	const n = 5000
+	doc := deepDoc(t, n)
+
+	limited := NewSelecter(doc, MaxDepth(100))
+	unlimited := NewSelecter(doc)
+
+	err := limited.Walk(func([]interface{}, Selecter) error { return nil })
+	if !errors.Is(err, ErrMaxDepth) {
+		t.Errorf("Walk = %v, want ErrMaxDepth", err)
+	}
+
+	_, err = limited.Select(Descend{Key: "x"})
+	if !errors.Is(err, ErrMaxDepth) {
+		t.Errorf("Descend = %v, want ErrMaxDepth", err)
+	}
+
+	_, err = limited.Flatten(".")
+	if !errors.Is(err, ErrMaxDepth) {
+		t.Errorf("Flatten = %v, want ErrMaxDepth", err)
+	}
+
+	count := 0
+	err = unlimited.Walk(func([]interface{}, Selecter) error {
+		count++
+		return nil
+	})
+	if err != nil || count != n+1 {
+		t.Errorf("Walk = %v after %d values, want nil after %d", err, count, n+1)
+	}
+
+	found, err := unlimited.SelectSlice(Descend{Key: "x"})
+	if err != nil || len(found) != n/2 {
+		t.Errorf("Descend = %d values, %v, want %d", len(found), err, n/2)
+	}
+
+	flat, err := unlimited.Flatten(".")
+	if err != nil || len(flat) != 1 {
+		t.Errorf("Flatten = %d leaves, %v, want 1", len(flat), err)
+	}
+}
+
+func TestNormalizeNumbers(t *testing.T) {
+	j, err := FromJSON([]byte(`{"a":1.5,"b":[2,1e21]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, set := range []struct {
+		key string
+		v   interface{}
+	}{
+		{"int", 3},
+		{"big", int64(1<<60 + 1)},
+		{"f32", float32(0.1)},
+		{"u8", uint8(7)},
+	} {
+		j, err = j.Set(set.v, set.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	norm := j.NormalizeNumbers()
+	err = norm.Walk(func(path []interface{}, val Selecter) error {
+		if kindOf(val.V) != KindNumber {
+			return nil
+		}
+
+		if _, ok := val.V.(float64); !ok {
+			t.Errorf("NormalizeNumbers left %v (%T) at %v", val.V, val.V, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the int64 loses precision as a float64
+	want := `{"a":1.5,"b":[2,1e+21],"big":1152921504606847000,"f32":0.1,"int":3,"u8":7}`
+	data, err := json.Marshal(norm.V)
+	if err != nil || string(data) != want {
+		t.Errorf("NormalizeNumbers marshaled to %s, %v, want %s", data, err, want)
+	}
+
+	normJSON := j.NormalizeNumbersJSON()
+	err = normJSON.Walk(func(path []interface{}, val Selecter) error {
+		if kindOf(val.V) != KindNumber {
+			return nil
+		}
+
+		if _, ok := val.V.(json.Number); !ok {
+			t.Errorf("NormalizeNumbersJSON left %v (%T) at %v", val.V, val.V, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = `{"a":1.5,"b":[2,1e+21],"big":1152921504606846977,"f32":0.1,"int":3,"u8":7}`
+	data, err = json.Marshal(normJSON.V)
+	if err != nil || string(data) != want {
+		t.Errorf("NormalizeNumbersJSON marshaled to %s, %v, want %s", data, err, want)
+	}
+
+	// the encoding matches that of the document before normalizing
+	orig, err := json.Marshal(j.V)
+	if err != nil || string(orig) != want {
+		t.Errorf("document marshaled to %s, %v, want %s", orig, err, want)
+	}
+}
@@ -1,9 +1,16 @@
 package json_select
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 
@@ -11,56 +18,725 @@ import (
 // its contents
 type Selecter struct {
 	V interface{}
+
+	opts options
+}
+
+// Option configures how a Selecter applies selectors, see NewSelecter
+type Option func(*options)
+
+// NullAsMissing makes a key or array element whose value is null be treated
+// as if it were not present. Selecting it returns ErrKeyNotPresent instead
+// of nil, so selecting "b" from a null "a" is reported as a missing "a"
+// rather than an attempt to select from a non-composite value. Selectors
+// which select many values (Star, slices, and Where) still select nulls.
+func NullAsMissing() Option {
+	return func(o *options) {
+		o.nullMissing = true
+	}
+}
+
+// IntKeys makes an int selector applied to an object select the key spelled
+// with the same decimal digits, so 0 selects the key "0", for documents
+// which represent sparse arrays as objects. Unlike for an array, a negative
+// int is not counted from the end, -1 selects the key "-1".
+func IntKeys() Option {
+	return func(o *options) {
+		o.intKeys = true
+	}
+}
+
+// MaxDepth limits how deeply Descend and Walk, and the methods built on
+// Walk such as Flatten, traverse nested values. Reaching a value nested more
+// than n levels below the value being traversed is an ErrMaxDepth. With
+// n <= 0, the default, there's no limit. encoding/json refuses to decode
+// documents nested more than 10000 levels deep, which is not deep enough
+// to overflow the stack, so the limit is for bounding the work done on
+// untrusted input and for hand built values which may be cyclic.
+func MaxDepth(n int) Option {
+	return func(o *options) {
+		o.maxDepth = n
+	}
+}
+
+// StrictInts makes SelectInt, and SelectIntSlice, return an error for a
+// float64 with a fractional part or outside of the range of an int instead
+// of truncating it. Integral values such as 3.0 are still converted.
+func StrictInts() Option {
+	return func(o *options) {
+		o.strictInts = true
+	}
+}
+
+// FloatIndexes makes a float64 selector with an integral value be used as
+// the int selector of the same value, so a number decoded by encoding/json
+// can be passed back as a selector as is. Without it, or if the float64 has
+// a fractional part, the selector is an ErrInvalidSelector.
+func FloatIndexes() Option {
+	return func(o *options) {
+		o.floatIndexes = true
+	}
+}
+
+// PartialResults makes the selectors which collect many values (Star,
+// KeyMatch, KeyPrefix, KeySuffix, Where, Descend, ClampRange, and the
+// []string and []int selectors) leave out the values which fail to be selected rather than
+// failing entirely, for best-effort extraction from messy data. Select
+// returns what could be selected along with an Errors holding the error of
+// each value left out, with its path. An empty result with an error means
+// nothing could be selected. The methods which convert the selection, such
+// as SelectSlice, fail on the error as usual.
+func PartialResults() Option {
+	return func(o *options) {
+		o.partialResults = true
+	}
+}
+
+// NewSelecter returns a Selecter for v configured with opts. The options are
+// kept by every Selecter derived from the returned one. Without any options
+// it's the same as Selecter{V: v}.
+func NewSelecter(v interface{}, opts ...Option) Selecter {
+	j := Selecter{V: v}
+	for _, opt := range opts {
+		opt(&j.opts)
+	}
+
+	return j
+}
+
+// New returns a Selecter for v after checking that v is made only of JSON
+// values: map[string]interface{}, *OrderedMap, []interface{}, nil, bool,
+// string, the Go numeric types and json.Number. An error with the path of
+// the first other value, such as a channel, a struct or a map[string]int,
+// or a NaN or infinite float which can't be encoded, is returned instead.
+// The check walks the whole of v, which must not be cyclic. Selecter{V: v}
+// can be used to skip the check.
+func New(v interface{}) (Selecter, error) {
+	err := checkJSON(v, []interface{}{})
+	if err != nil {
+		return Selecter{}, err
+	}
+
+	return Selecter{V: v}, nil
+}
+
+func checkJSON(v interface{}, path []interface{}) error {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(vv) {
+			err := checkJSON(vv[k], append(path, k))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case *OrderedMap:
+		for _, k := range vv.keys {
+			err := checkJSON(vv.values[k], append(path, k))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case []interface{}:
+		for i, elem := range vv {
+			err := checkJSON(elem, append(path, i))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	switch kindOf(v) {
+	case KindNull, KindBool, KindString:
+		return nil
+	case KindNumber:
+		if _, ok := v.(json.Number); ok {
+			return nil
+		}
+
+		f, _ := toFloat(v)
+		if !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return nil
+		}
+	}
+
+	msg := fmt.Sprintf("%v (%T) not a JSON value", v, v)
+	if len(path) > 0 {
+		msg += " at path " + formatPath(path)
+	}
+
+	return errors.New(msg)
 }
 
 // Select returns a Selecter for the query
 func (j Selecter) Select(sels ...interface{}) (Selecter, error) {
-	v, err := Select(j.V, sels...)
-	return Selecter{V: v}, err
+	v, err := j.selectValue(sels)
+	return j.wrap(v), err
+}
+
+// selectValue is Select with the options of j. A single key of a
+// map[string]interface{} or element of a []interface{} is selected with
+// Field or Index.
+func (j Selecter) selectValue(sels []interface{}) (interface{}, error) {
+	if len(sels) == 1 {
+		switch sel := sels[0].(type) {
+		case string:
+			if _, ok := j.V.(map[string]interface{}); ok {
+				v, err := j.Field(sel)
+				return v.V, err
+			}
+
+		case int:
+			if _, ok := j.V.([]interface{}); ok {
+				v, err := j.Index(sel)
+				return v.V, err
+			}
+		}
+	}
+
+	return j.opts.selectRoot(j.V, sels)
+}
+
+// wrap returns a Selecter for v with the options of j
+func (j Selecter) wrap(v interface{}) Selecter {
+	return Selecter{V: v, opts: j.opts}
+}
+
+// Field is like Select with a single string selector, but a key of a
+// map[string]interface{} is looked up directly, without the allocation of
+// the variadic selectors or Select's type switches.
+func (j Selecter) Field(key string) (Selecter, error) {
+	objv, ok := j.V.(map[string]interface{})
+	if !ok {
+		return j.Select(key)
+	}
+
+	v, ok, err := j.opts.key(objv, key)
+	if err != nil {
+		return j.wrap(nil), err
+	}
+
+	if !ok {
+		return j.wrap(nil), j.opts.keyNotPresent(objv, key)
+	}
+
+	return j.wrap(v), nil
+}
+
+// Index is like Field but for a single int selector, selecting an element
+// of a []interface{} directly.
+func (j Selecter) Index(i int) (Selecter, error) {
+	objv, ok := j.V.([]interface{})
+	if !ok {
+		return j.Select(i)
+	}
+
+	v, _, err := j.opts.index(objv, i)
+	return j.wrap(v), err
+}
+
+// SelectContext is like Select but stops and returns ctx.Err() if ctx is
+// done before the selection is finished. The context is checked
+// periodically, which only matters for selectors which may visit many
+// values such as Descend or Wildcard.
+func (j Selecter) SelectContext(ctx context.Context, sels ...interface{}) (Selecter, error) {
+	o := j.opts
+	o.ctx = &ctxCheck{ctx: ctx}
+
+	v, err := o.selectRoot(j.V, sels)
+	return j.wrap(v), err
+}
+
+// SelectFold is like Select but string selectors match object keys ignoring
+// case. An exact match is always preferred, otherwise the keys are compared
+// using strings.EqualFold. If more than one key matches (e.g. the object has
+// both "Name" and "NAME" and the selector is "name") an error is returned.
+func (j Selecter) SelectFold(sels ...interface{}) (Selecter, error) {
+	o := j.opts
+	o.fold = true
+
+	v, err := o.selectRoot(j.V, sels)
+	return j.wrap(v), err
+}
+
+// SelectAny tries each of paths as the selectors for Select, in order, and
+// returns the first selection which succeeds. If none succeed the error is
+// an Errors with the error of each path.
+func (j Selecter) SelectAny(paths ...[]interface{}) (Selecter, error) {
+	errs := make(Errors, 0, len(paths))
+	for _, sels := range paths {
+		v, err := j.Select(sels...)
+		if err == nil {
+			return v, nil
+		}
+
+		errs = append(errs, fmt.Errorf("path %v: %w", sels, err))
+	}
+
+	if len(errs) == 0 {
+		return j.wrap(nil), fmt.Errorf("no paths to select")
+	}
+
+	return j.wrap(nil), errs
+}
+
+// SelectFields selects each path of spec and returns the selections keyed
+// by the same names as in spec, for example
+//		j.SelectFields(map[string][]interface{}{
+//			"first": {"menu", 0, "name"},
+//			"count": {"count"},
+//		})
+// The paths are selected in the sorted order of their names. If any of them
+// fail the error is an Errors with the error of each failed path, and the
+// map still holds the selections which succeeded. See SelectFieldsFailFast
+// for stopping at the first error.
+func (j Selecter) SelectFields(spec map[string][]interface{}) (map[string]Selecter, error) {
+	return j.selectFields(spec, false)
+}
+
+// SelectFieldsFailFast is like SelectFields but stops at the first path
+// which fails and returns its error, along with a nil map.
+func (j Selecter) SelectFieldsFailFast(spec map[string][]interface{}) (map[string]Selecter, error) {
+	return j.selectFields(spec, true)
+}
+
+func (j Selecter) selectFields(spec map[string][]interface{}, failFast bool) (map[string]Selecter, error) {
+	names := make([]string, 0, len(spec))
+	for name := range spec {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var errs Errors
+	ret := make(map[string]Selecter, len(spec))
+	for _, name := range names {
+		v, err := j.Select(spec[name]...)
+		if err != nil {
+			err = fmt.Errorf("field %q: %w", name, err)
+			if failFast {
+				return nil, err
+			}
+
+			errs = append(errs, err)
+			continue
+		}
+
+		ret[name] = v
+	}
+
+	if len(errs) > 0 {
+		return ret, errs
+	}
+
+	return ret, nil
+}
+
+// SelectAs is like Select but attempts to coerce the selection into a T with
+// a type assertion, no conversions are done. For example
+//		SelectAs[[]interface{}](j, "menu")
+//		SelectAs[map[string]interface{}](j, "menu", 0)
+// An error is returned if the assertion fails, or ErrNilValue if the
+// selection is null.
+func SelectAs[T any](j Selecter, sels ...interface{}) (T, error) {
+	var zero T
+
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return zero, err
+	}
+
+	if v == nil {
+		return zero, fmt.Errorf("%w: %v", ErrNilValue, sels)
+	}
+
+	t, ok := v.(T)
+	if !ok {
+		// zero is nil when T is an interface, so %T can't be used
+		return zero, coercionError(v,
+			reflect.TypeOf((*T)(nil)).Elem().String(), nil)
+	}
+
+	return t, nil
+}
+
+// SelectWith is like Select but converts the selection with coerce, for
+// conversions which don't have a method of their own, e.g. parsing a color
+// from a hex string. coerce is passed the selected value and its result is
+// returned as is.
+func (j Selecter) SelectWith(coerce func(interface{}) (interface{}, error), sels ...interface{}) (interface{}, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return nil, err
+	}
+
+	return coerce(v)
+}
+
+// SelectOrDefault is like Select but returns a Selecter for def instead of an
+// error. def is used when the selection is missing (ErrKeyNotPresent), when
+// it is present but null, and also for any other error Select may return,
+// such as selecting a key from a scalar value. The result can be chained
+// with the other methods, e.g.
+//		port, err := cfg.SelectOrDefault(8080, "server", "port").SelectInt()
+func (j Selecter) SelectOrDefault(def interface{}, sels ...interface{}) Selecter {
+	v, err := j.selectValue(sels)
+	if err != nil || v == nil {
+		return j.wrap(def)
+	}
+
+	return j.wrap(v)
+}
+
+// Exists reports whether the selection exists. A selection which is
+// present but null exists. Unlike Select, no error values are constructed
+// for missing keys or for a string or int selector applied to a JSON value
+// of the wrong type, such as a key of an array, both of which are reported
+// as false.
+func (j Selecter) Exists(sels ...interface{}) bool {
+	_, ok := j.opts.lookup(j.V, sels)
+	return ok
+}
+
+// Get is like Select but reports whether the selection exists with a bool
+// instead of an error, like a comma-ok map access. As with Exists, no error
+// values are constructed when the selection fails.
+func (j Selecter) Get(sels ...interface{}) (Selecter, bool) {
+	v, ok := j.opts.lookup(j.V, sels)
+	if !ok {
+		return j.wrap(nil), false
+	}
+
+	return j.wrap(v), true
+}
+
+// Raw is like Get but returns the selected value itself rather than a
+// Selecter, for callers which do their own type handling.
+func (j Selecter) Raw(sels ...interface{}) (interface{}, bool) {
+	return j.opts.lookup(j.V, sels)
 }
 
 // SelectBool is like Select but attempts to coerce the selection into a bool.
 // An error is returned if the coercion fails
 func (j Selecter) SelectBool(sels ...interface{}) (bool, error) {
-	v, err := Select(j.V, sels...)
+	v, err := j.selectValue(sels)
 	if err != nil {
 		return false, err
 	}
 
 	boolean, ok := v.(bool)
 	if !ok {
-		return false, fmt.Errorf("%v not a bool", v)
+		return false, coercionError(v, "bool", nil)
 	}
 
 	return boolean, nil
 }
 
+// SelectBoolCoerce is like SelectBool but also converts a string using
+// strconv.ParseBool, which accepts "true", "false", "1", "0", and a few
+// other spellings. An error is returned for any other string.
+func (j Selecter) SelectBoolCoerce(sels ...interface{}) (bool, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return false, err
+	}
+
+	switch vv := v.(type) {
+	case bool:
+		return vv, nil
+	case string:
+		b, err := strconv.ParseBool(vv)
+		if err != nil {
+			return false, coercionError(vv, "bool", nil)
+		}
+
+		return b, nil
+	default:
+		return false, coercionError(v, "bool", nil)
+	}
+}
+
 // SelectInt is like Select but attempts to coerce and convert the selection
 // into an int. An error is returned if the coercion or conversion fails. The
 // followin types are supported:
 //		int
-//		float64
+//		float64 (truncated, unless using the StrictInts option)
+//		json.Number (exactly if it's an integer, otherwise as a float64)
 //		string (using strconv.Atoi)
 func (j Selecter) SelectInt(sels ...interface{}) (int, error) {
-	v, err := Select(j.V, sels...)
+	v, err := j.selectValue(sels)
 	if err != nil {
 		return 0, err
 	}
 
+	if n, ok := v.(json.Number); ok {
+		i, err := strconv.ParseInt(string(n), 10, strconv.IntSize)
+		if err == nil {
+			return int(i), nil
+		}
+
+		v, err = n.Float64()
+		if err != nil {
+			return 0, coercionError(n, "int", nil)
+		}
+	}
+
 	switch vv := v.(type) {
 	case int:
 		return vv, nil
 	case float64:
+		// math.MinInt is exactly representable, -math.MinInt is the first
+		// value out of range
+		if j.opts.strictInts && (vv != math.Trunc(vv) ||
+			vv < math.MinInt || vv >= -math.MinInt) {
+			return 0, coercionError(v, "int", nil)
+		}
+
 		return int(vv), nil
 	case string:
 		i, err := strconv.Atoi(vv)
 		if err != nil {
-			return 0, fmt.Errorf("%v not a int", v)
+			return 0, coercionError(v, "int", nil)
+		}
+
+		return i, nil
+	default:
+		return 0, coercionError(v, "int", nil)
+	}
+}
+
+// SelectIntExact is like SelectInt but also reports whether the conversion
+// was exact, rather than an error or a silent truncation depending on the
+// StrictInts option, which is ignored. A float64, or a json.Number which
+// isn't an integer, with a fractional part is truncated as by SelectInt and
+// exact is false, so 2.0 is exact and 2.5 is not. A number or string out of
+// the range of an int is clamped to math.MinInt or math.MaxInt and exact is
+// false. NaN, and anything SelectInt can't convert, is still an error.
+func (j Selecter) SelectIntExact(sels ...interface{}) (n int, exact bool, err error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if num, ok := v.(json.Number); ok {
+		i, err := strconv.ParseInt(string(num), 10, strconv.IntSize)
+		if err == nil {
+			return int(i), true, nil
+		}
+
+		f, err := num.Float64()
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return 0, false, coercionError(num, "int", nil)
+		}
+
+		v = f
+	}
+
+	switch vv := v.(type) {
+	case int:
+		return vv, true, nil
+	case float64:
+		switch {
+		case math.IsNaN(vv):
+			return 0, false, coercionError(v, "int", nil)
+		case vv < math.MinInt:
+			return math.MinInt, false, nil
+		case vv >= -math.MinInt:
+			return math.MaxInt, false, nil
+		}
+
+		return int(vv), vv == math.Trunc(vv), nil
+	case string:
+		i, err := strconv.Atoi(vv)
+		if errors.Is(err, strconv.ErrRange) {
+			return i, false, nil
+		}
+
+		if err != nil {
+			return 0, false, coercionError(v, "int", nil)
+		}
+
+		return i, true, nil
+	default:
+		return 0, false, coercionError(v, "int", nil)
+	}
+}
+
+// SelectIntBase is like SelectInt but a string is parsed in the given base
+// using strconv.ParseInt, so with a base of 16 "1F" is 31. A base of 0
+// detects the base from a prefix of the string, such as "0x1F" or "0o755".
+// Any other selection is converted the same as by SelectInt.
+func (j Selecter) SelectIntBase(base int, sels ...interface{}) (int, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return 0, err
+	}
+
+	str, ok := v.(string)
+	if !ok {
+		return j.wrap(v).SelectInt()
+	}
+
+	i, err := strconv.ParseInt(str, base, strconv.IntSize)
+	if err != nil {
+		return 0, coercionError(str, "int", err)
+	}
+
+	return int(i), nil
+}
+
+// SelectInt64 is like SelectInt but always converts into an int64, which is
+// not subject to the width of the platform's int. Unlike SelectInt, a float64
+// with a fractional part or outside of the range of an int64 is an error
+// rather than being truncated. The followin types are supported:
+//		int
+//		float64
+//		json.Number (exactly if it's an integer, otherwise as a float64)
+//		string (using strconv.ParseInt)
+func (j Selecter) SelectInt64(sels ...interface{}) (int64, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return 0, err
+	}
+
+	if v == nil {
+		return 0, fmt.Errorf("%w: %v", ErrNilValue, sels)
+	}
+
+	if n, ok := v.(json.Number); ok {
+		i, err := n.Int64()
+		if err == nil {
+			return i, nil
+		}
+
+		v, err = n.Float64()
+		if err != nil {
+			return 0, coercionError(n, "int64", nil)
+		}
+	}
+
+	switch vv := v.(type) {
+	case int:
+		return int64(vv), nil
+	case float64:
+		// -2^63 is exactly representable, 2^63 is the first value out of
+		// range
+		if vv != math.Trunc(vv) || vv < math.MinInt64 || vv >= -math.MinInt64 {
+			return 0, coercionError(v, "int64", nil)
+		}
+
+		return int64(vv), nil
+	case string:
+		i, err := strconv.ParseInt(vv, 10, 64)
+		if err != nil {
+			return 0, coercionError(vv, "int64", err)
 		}
 
 		return i, nil
 	default:
-		return 0, fmt.Errorf("%v (%T) not a int", v, v)
+		return 0, coercionError(v, "int64", nil)
+	}
+}
+
+// SelectUint64 is like SelectInt64 but converts into a uint64. Negative
+// values are an error. The followin types are supported:
+//		int
+//		float64
+//		json.Number (exactly if it's an integer, otherwise as a float64)
+//		string (using strconv.ParseUint)
+func (j Selecter) SelectUint64(sels ...interface{}) (uint64, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return 0, err
+	}
+
+	if v == nil {
+		return 0, fmt.Errorf("%w: %v", ErrNilValue, sels)
+	}
+
+	if n, ok := v.(json.Number); ok {
+		i, err := strconv.ParseUint(string(n), 10, 64)
+		if err == nil {
+			return i, nil
+		}
+
+		v, err = n.Float64()
+		if err != nil {
+			return 0, coercionError(n, "uint64", nil)
+		}
+	}
+
+	switch vv := v.(type) {
+	case int:
+		if vv < 0 {
+			return 0, coercionError(v, "uint64", nil)
+		}
+
+		return uint64(vv), nil
+	case float64:
+		// 2^64 is the first value out of range
+		if vv != math.Trunc(vv) || vv < 0 || vv >= 2*(-math.MinInt64) {
+			return 0, coercionError(v, "uint64", nil)
+		}
+
+		return uint64(vv), nil
+	case string:
+		i, err := strconv.ParseUint(vv, 10, 64)
+		if err != nil {
+			return 0, coercionError(vv, "uint64", err)
+		}
+
+		return i, nil
+	default:
+		return 0, coercionError(v, "uint64", nil)
+	}
+}
+
+// SelectFloat is like Select but attempts to coerce and convert the selection
+// into a float64. An error is returned if the coercion or conversion fails.
+// The followin types are supported:
+//		float64
+//		int
+//		json.Number
+//		string (using strconv.ParseFloat)
+func (j Selecter) SelectFloat(sels ...interface{}) (float64, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return 0, err
+	}
+
+	if v == nil {
+		return 0, fmt.Errorf("%w: %v", ErrNilValue, sels)
+	}
+
+	switch vv := v.(type) {
+	case float64:
+		return vv, nil
+	case int:
+		return float64(vv), nil
+	case json.Number:
+		f, err := vv.Float64()
+		if err != nil {
+			return 0, coercionError(vv, "float64", err)
+		}
+
+		return f, nil
+	case string:
+		f, err := strconv.ParseFloat(vv, 64)
+		if err != nil {
+			return 0, coercionError(vv, "float64", err)
+		}
+
+		return f, nil
+	default:
+		return 0, coercionError(v, "float64", nil)
 	}
 }
 
@@ -68,7 +744,7 @@ func (j Selecter) SelectInt(sels ...interface{}) (int, error) {
 // SelectString is like Select but attempts to coerce the selection into a
 // string. An error is returned if the coercion fails.
 func (j Selecter) SelectString(sels ...interface{}) (string, error) {
-	v, err := Select(j.V, sels...)
+	v, err := j.selectValue(sels)
 	if err != nil {
 		return "", err
 	}
@@ -79,231 +755,1224 @@ func (j Selecter) SelectString(sels ...interface{}) (string, error) {
 
 	str, ok := v.(string)
 	if !ok {
-		return "", fmt.Errorf("%v not a string", v)
+		return "", coercionError(v, "string", nil)
 	}
 
 	return str, nil
 }
 
+// SelectScalar is like Select but returns the selection only if it's a JSON
+// scalar: a bool, a number, a string or null, which is returned as nil. The
+// value is returned as is, e.g. a float64 or a json.Number depending on how
+// the document was decoded. An error is returned if the selection is an
+// object, an array or isn't a JSON value.
+func (j Selecter) SelectScalar(sels ...interface{}) (interface{}, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kindOf(v) {
+	case KindObject, KindArray, KindInvalid:
+		return nil, fmt.Errorf("%v (%v) not a scalar", v, kindOf(v))
+	}
+
+	return v, nil
+}
+
+// SelectBytes is like Select but attempts to coerce the selection into a
+// string and decode it with base64.StdEncoding. An error is returned if the
+// coercion or decoding fails. A selection which is already a []byte is
+// returned as is.
+func (j Selecter) SelectBytes(sels ...interface{}) ([]byte, error) {
+	return j.selectBytes(base64.StdEncoding, sels)
+}
+
+// SelectBytesURL is like SelectBytes but decodes with base64.URLEncoding
+func (j Selecter) SelectBytesURL(sels ...interface{}) ([]byte, error) {
+	return j.selectBytes(base64.URLEncoding, sels)
+}
+
+func (j Selecter) selectBytes(enc *base64.Encoding, sels []interface{}) ([]byte, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		return nil, fmt.Errorf("%w: %v", ErrNilValue, sels)
+	}
+
+	switch vv := v.(type) {
+	case []byte:
+		return vv, nil
+	case string:
+		b, err := enc.DecodeString(vv)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode base64 at %v: %w", sels, err)
+		}
+
+		return b, nil
+	default:
+		return nil, coercionError(v, "[]byte", nil)
+	}
+}
+
 // SelectSlice is like Select but attempts to coerce the selection into a
-// []interface{} (which gets converted into []Selecter). An error is
-// returned if the coercion fails.
+// []interface{} (which gets converted into []Selecter). A non-nil
+// *[]interface{} is dereferenced, but only one level of pointer is. An
+// error is returned if the coercion fails.
 func (j Selecter) SelectSlice(sels ...interface{}) ([]Selecter, error) {
-	v, err := Select(j.V, sels...)
+	return j.SelectSliceInto(nil, sels...)
+}
+
+// SelectSliceInto is like SelectSlice but reuses the backing array of dst
+// for the result if it has the capacity, so that selecting from many
+// documents in a loop doesn't allocate a new slice each time. The result
+// replaces the contents of dst, dst[:0] is returned with an error.
+func (j Selecter) SelectSliceInto(dst []Selecter, sels ...interface{}) ([]Selecter, error) {
+	v, err := j.selectValue(sels)
 	if err != nil {
-		return nil, err
+		return dst[:0], err
+	}
+
+	if p, ok := v.(*[]interface{}); ok && p != nil {
+		v = *p
 	}
 
 	slcv, ok := v.([]interface{})
 	if !ok {
-		return nil, fmt.Errorf("%v not a slice", v)
+		return dst[:0], coercionError(v, "[]interface{}", nil)
+	}
+
+	// a nil dst still gets a non-nil result, so an empty array isn't
+	// marshaled as null
+	if dst == nil || cap(dst) < len(slcv) {
+		dst = make([]Selecter, len(slcv))
 	}
 
-	slc := make([]Selecter, len(slcv))
+	dst = dst[:len(slcv)]
 	for i, v := range slcv {
-		slc[i] = Selecter{V: v}
+		dst[i] = j.wrap(v)
+	}
+
+	return dst, nil
+}
+
+// SelectStringSlice is like SelectSlice but also coerces each element into a
+// string, as with SelectString. An error identifying the index of the first
+// element which isn't a string is returned if the coercion fails.
+func (j Selecter) SelectStringSlice(sels ...interface{}) ([]string, error) {
+	slc, err := j.SelectSlice(sels...)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]string, len(slc))
+	for i, v := range slc {
+		ret[i], err = v.SelectString()
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+
+	return ret, nil
+}
+
+// SelectIntSlice is like SelectStringSlice but coerces each element into an
+// int, as with SelectInt.
+func (j Selecter) SelectIntSlice(sels ...interface{}) ([]int, error) {
+	slc, err := j.SelectSlice(sels...)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]int, len(slc))
+	for i, v := range slc {
+		ret[i], err = v.SelectInt()
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+
+	return ret, nil
+}
+
+// SelectFloatSlice is like SelectStringSlice but coerces each element into a
+// float64, as with SelectFloat.
+func (j Selecter) SelectFloatSlice(sels ...interface{}) ([]float64, error) {
+	slc, err := j.SelectSlice(sels...)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]float64, len(slc))
+	for i, v := range slc {
+		ret[i], err = v.SelectFloat()
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+
+	return ret, nil
+}
+
+// SelectMap is like Select but attempts to coerce the selection into a
+// map[string]interface{} (which gets converted into map[string]Selecter).
+// A non-nil *map[string]interface{} is dereferenced, but only one level of
+// pointer is. An error is returned if the coercion fails.
+func (j Selecter) SelectMap(sels ...interface{}) (map[string]Selecter, error) {
+	return j.SelectMapInto(nil, sels...)
+}
+
+// SelectMapInto is like SelectMap but clears dst and fills it with the
+// result, so that selecting from many documents in a loop can reuse the
+// same map. A new map is made if dst is nil. dst is cleared, but otherwise
+// left untouched, if there's an error.
+func (j Selecter) SelectMapInto(dst map[string]Selecter, sels ...interface{}) (map[string]Selecter, error) {
+	for k := range dst {
+		delete(dst, k)
+	}
+
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return dst, err
+	}
+
+	if p, ok := v.(*map[string]interface{}); ok && p != nil {
+		v = *p
+	}
+
+	mapv, ok := v.(map[string]interface{})
+	if !ok {
+		return dst, coercionError(v, "map[string]interface{}", nil)
+	}
+
+	if dst == nil {
+		dst = make(map[string]Selecter, len(mapv))
+	}
+
+	for k, v := range mapv {
+		dst[k] = j.wrap(v)
+	}
+
+	return dst, nil
+}
+
+// SelectMapString is like Select but attempts to coerce the selection into a
+// map[string]string (which gets converted into map[string]string). An error
+// is returned if the coercion fails.
+func (j Selecter) SelectMapString(sels ...interface{}) (map[string]string, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return nil, err
+	}
+
+	mapv, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, coercionError(v, "map[string]interface{}", nil)
+	}
+
+	mp := make(map[string]string, len(mapv))
+	for k, v := range mapv {
+		mp[k], err = j.wrap(v).SelectString()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return mp, nil
+}
+
+// SelectMapStringCoerce is like SelectMapString but values which are not a
+// string are converted to their JSON encoding instead of being an error, so
+// {"port": 8080, "tls": {"on": true}} gives {"port": "8080", "tls":
+// "{\"on\":true}"}. A null value becomes "null".
+func (j Selecter) SelectMapStringCoerce(sels ...interface{}) (map[string]string, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return nil, err
+	}
+
+	mapv, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, coercionError(v, "map[string]interface{}", nil)
+	}
+
+	mp := make(map[string]string, len(mapv))
+	for k, v := range mapv {
+		if str, ok := v.(string); ok {
+			mp[k] = str
+			continue
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k, err)
+		}
+
+		mp[k] = string(b)
+	}
+
+	return mp, nil
+}
+
+// SelectMapSlice is like SelectSlice but coerces each element into a
+// map[string]Selecter, as with SelectMap. It's meant for the common case
+// of an array of objects.
+func (j Selecter) SelectMapSlice(sels ...interface{}) ([]map[string]Selecter, error) {
+	slc, err := j.SelectSlice(sels...)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]map[string]Selecter, len(slc))
+	for i, v := range slc {
+		ret[i], err = v.SelectMap()
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+
+	return ret, nil
+}
+
+// Pluck is like SelectSlice but selects field from each element of the
+// selection, which is the same as SelectSlice(append(sels, []int{}, field)...)
+// except that an error says which element the field couldn't be selected
+// from. See PluckPresent for skipping the elements which lack the field.
+func (j Selecter) Pluck(field string, sels ...interface{}) ([]Selecter, error) {
+	return j.pluck(field, false, sels)
+}
+
+// PluckPresent is like Pluck but skips the elements which lack field instead
+// of returning an ErrKeyNotPresent. Any other error, such as an element which
+// is not an object, is still returned.
+func (j Selecter) PluckPresent(field string, sels ...interface{}) ([]Selecter, error) {
+	return j.pluck(field, true, sels)
+}
+
+func (j Selecter) pluck(field string, skipMissing bool, sels []interface{}) ([]Selecter, error) {
+	slc, err := j.SelectSlice(sels...)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]Selecter, 0, len(slc))
+	for i, v := range slc {
+		v, err := v.Select(field)
+		if err != nil {
+			if skipMissing && errors.Is(err, ErrKeyNotPresent{}) {
+				continue
+			}
+
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+
+		ret = append(ret, v)
+	}
+
+	return ret, nil
+}
+
+// Len is like Select but returns the number of elements of the selection,
+// which must be a []interface{} or map[string]interface{}. An error is
+// returned for any other value.
+func (j Selecter) Len(sels ...interface{}) (int, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return 0, err
+	}
+
+	switch vv := v.(type) {
+	case []interface{}:
+		return len(vv), nil
+	case map[string]interface{}:
+		return len(vv), nil
+	case *OrderedMap:
+		return vv.Len(), nil
+	default:
+		return 0, fmt.Errorf("%v (%T) has no length", v, v)
+	}
+}
+
+// Keys is like Select but returns the keys of the selection, which must be a
+// map[string]interface{} or *OrderedMap. The keys of a map[string]interface{}
+// are in Go's (undefined) map order, see SortedKeys for a deterministic
+// order, and the keys of an *OrderedMap are in order.
+func (j Selecter) Keys(sels ...interface{}) ([]string, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return nil, err
+	}
+
+	if om, ok := v.(*OrderedMap); ok {
+		return om.Keys(), nil
+	}
+
+	mapv, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, coercionError(v, "map[string]interface{}", nil)
+	}
+
+	keys := make([]string, 0, len(mapv))
+	for k := range mapv {
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
+
+// SortedKeys is like Keys but the keys are sorted
+func (j Selecter) SortedKeys(sels ...interface{}) ([]string, error) {
+	keys, err := j.Keys(sels...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Errors is a list of errors returned as a single error, for example when
+// each of the selections tried by SelectAny fails. It supports errors.Is
+// and errors.As matching any of the errors, with the Is and As methods
+// for versions of Go before 1.20 which don't use Unwrap() []error.
+type Errors []error
+
+func (errs Errors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func (errs Errors) Unwrap() []error {
+	return errs
+}
+
+// Is reports whether any of errs matches target, see errors.Is
+func (errs Errors) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first of errs which matches target, see errors.As
+func (errs Errors) As(target interface{}) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
 	}
 
-	return slc, nil
+	return false
 }
 
-// SelectMap is like Select but attempts to coerce the selection into a
-// map[string]interface{} (which gets converted into map[string]Selecter).
-// An error is returned if the coercion fails.
-func (j Selecter) SelectMap(sels ...interface{}) (map[string]Selecter, error) {
-	v, err := Select(j.V, sels...)
-	if err != nil {
-		return nil, err
-	}
+// ErrInvalidSelector is returned when a selector is not supported by Select.
+// Selector is the unsupported selector and Index is its position in the
+// list of selectors.
+type ErrInvalidSelector struct {
+	Selector interface{}
+	Index    int
 
-	mapv, ok := v.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("%v not a map", v)
-	}
+	msg string
+}
 
-	mp := make(map[string]Selecter, len(mapv))
-	for k, v := range mapv {
-		mp[k] = Selecter{V: v}
-	}
+func (err ErrInvalidSelector) Error() string {
+	return fmt.Sprintf("selector %d: %s", err.Index, err.msg)
+}
 
-	return mp, nil
+// ErrCoercion is returned by the methods such as SelectInt and SelectString
+// when the selection can't be converted into the type of the method, so a
+// validation layer can report that a field should be an int but was a
+// string. Type is the Go type of Value, Target is the Go type which it can't
+// be converted into, and Err is the error from parsing a string, if any.
+type ErrCoercion struct {
+	Value  interface{}
+	Type   string
+	Target string
+	Err    error
 }
 
-// SelectMapString is like Select but attempts to coerce the selection into a
-// map[string]string (which gets converted into map[string]string). An error
-// is returned if the coercion fails.
-func (j Selecter) SelectMapString(sels ...interface{}) (map[string]string, error) {
-	v, err := Select(j.V, sels...)
-	if err != nil {
-		return nil, err
+func coercionError(v interface{}, target string, err error) error {
+	return ErrCoercion{
+		Value:  v,
+		Type:   fmt.Sprintf("%T", v),
+		Target: target,
+		Err:    err,
 	}
+}
 
-	mapv, ok := v.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("%v not a slice", v)
+func (err ErrCoercion) Error() string {
+	var msg string
+	if str, ok := err.Value.(string); ok {
+		msg = fmt.Sprintf("%q not a %s", str, err.Target)
+	} else {
+		msg = fmt.Sprintf("%v (%s) not a %s", err.Value, err.Type, err.Target)
 	}
 
-	mp := make(map[string]string, len(mapv))
-	for k, v := range mapv {
-		mp[k], err = Selecter{V: v}.SelectString()
-		if err != nil {
-			return nil, err
-		}
+	if err.Err != nil {
+		msg += ": " + err.Err.Error()
 	}
 
-	return mp, nil
+	return msg
+}
+
+func (err ErrCoercion) Unwrap() error {
+	return err.Err
 }
 
+// ErrMaxDepth is returned when a traversal reaches a value nested more
+// deeply than allowed by the MaxDepth option
+var ErrMaxDepth = errors.New("max depth exceeded")
+
 var ErrNilValue = errors.New("key exists but but nil value cannot be converted")
 
+// ErrKeyNotPresent is returned when a selection does not exist. Key is the
+// selector that failed and Path is the selectors (or the keys and indices
+// chosen by a multi-value selector) leading up to it.
 type ErrKeyNotPresent struct {
-	Key interface{}
+	Key  interface{}
+	Path []interface{}
+
+	// null is set if the key is present but null, and the NullAsMissing
+	// option is set
+	null bool
 }
 
 func (err ErrKeyNotPresent) Error() string {
-	f := ""
+	msg := err.keyError()
+	if len(err.Path) > 0 {
+		msg += " at path " + formatPath(err.Path)
+	}
+
+	return msg
+}
+
+func (err ErrKeyNotPresent) keyError() string {
 	switch key := err.Key.(type) {
 	case string:
-		f = "key %q not found in object"
+		if err.null {
+			return fmt.Sprintf("key %q is null", key)
+		}
+
+		return fmt.Sprintf("key %q not found in object", key)
 	case []int:
-		switch len(key) {
-		case 2: //
-			f = "index %v out of bounds for array of len " +
-				strconv.Itoa(key[1])
+		if err.null && len(key) == 2 {
+			return fmt.Sprintf("index %v is null", key[0])
+		}
 
-		case 3:
-			f = "indeces %v out of bounds for array of len " +
-				strconv.Itoa(key[2])
+		// the last element is the length of the array, the rest is the
+		// selector as passed by the caller: an int index, or an []int
+		// slice selector with up to 3 elements
+		switch len(key) {
+		case 2:
+			return fmt.Sprintf("index %v out of bounds for array of len %d",
+				key[0], key[1])
 
-		default:
-			f = "key %v (%T) not found in object"
+		case 3, 4:
+			return fmt.Sprintf("indeces %v out of bounds for array of len %d",
+				key[:len(key)-1], key[len(key)-1])
 		}
-	default:
-		// should not happen
-		f = "key %v (%T) not found in object"
 	}
 
-	return fmt.Sprintf(f, err.Key)
+	// should not happen
+	return fmt.Sprintf("key %v (%T) not found in object", err.Key, err.Key)
 }
 
+// Is matches arg if it is an ErrKeyNotPresent (or a pointer to one) with
+// the same Key, the Path is only compared if arg has one. If arg is the zero
+// value it's just a type check.
 func (err ErrKeyNotPresent) Is(arg error) bool {
-	argv, ok := arg.(ErrKeyNotPresent)
-	if !ok {
-		argp, ok := arg.(*ErrKeyNotPresent)
-		if !ok {
+	var argv ErrKeyNotPresent
+	switch arg := arg.(type) {
+	case ErrKeyNotPresent:
+		argv = arg
+	case *ErrKeyNotPresent:
+		if arg == nil {
 			return false
 		}
 
-		arg = *argp
+		argv = *arg
+	default:
+		return false
 	}
 
-	// of arg is 0 value it's just a type check
-	if argv == (ErrKeyNotPresent{}) {
+	if argv.Key == nil && argv.Path == nil {
 		return true
 	}
 
-	return err == argv
+	if !reflect.DeepEqual(err.Key, argv.Key) {
+		return false
+	}
+
+	return argv.Path == nil || reflect.DeepEqual(err.Path, argv.Path)
+}
+
+// ErrNotIndexable is returned when a selector is applied to a value which
+// can't be selected from, such as a key of a string or number. Value is the
+// value, whose Go type can be found with %T or reflect.TypeOf, Selector is
+// the selector, and Path is the selectors leading up to the value as with
+// ErrKeyNotPresent. Selecting from a null value is an ErrNotIndexable with
+// a nil Value, which also matches ErrNullTraversal with errors.Is.
+type ErrNotIndexable struct {
+	Value    interface{}
+	Selector interface{}
+	Path     []interface{}
+}
+
+// ErrNullTraversal matches an ErrNotIndexable for a selection which tried to
+// continue through a null value, see ErrNotIndexable.
+var ErrNullTraversal = errors.New("cannot select from null")
+
+func (err ErrNotIndexable) Error() string {
+	msg := fmt.Sprintf("cannot select field %v of %v", err.Selector, err.Value)
+	if err.Value == nil {
+		msg = fmt.Sprintf("cannot select %v from null", err.Selector)
+	}
+
+	if len(err.Path) > 0 {
+		msg += " at path " + formatPath(err.Path)
+	}
+
+	return msg
+}
+
+// Is matches ErrNullTraversal if the value is null
+func (err ErrNotIndexable) Is(arg error) bool {
+	return arg == ErrNullTraversal && err.Value == nil
+}
+
+// pathError adds the path of a selection to an error which doesn't have a
+// Path of its own, inPath builds up the path like that of ErrKeyNotPresent
+type pathError struct {
+	path []interface{}
+	err  error
+}
+
+func (err pathError) Error() string {
+	msg := err.err.Error()
+	if len(err.path) > 0 {
+		msg += " at path " + formatPath(err.path)
+	}
+
+	return msg
+}
+
+func (err pathError) Unwrap() error {
+	return err.err
+}
+
+// inPath adds sel to the front of the path of err, if it has one. It's used
+// as errors are returned up through the recursive calls selecting into
+// values.
+func inPath(err error, sel interface{}) error {
+	switch errv := err.(type) {
+	case ErrKeyNotPresent:
+		errv.Path = append([]interface{}{sel}, errv.Path...)
+		return errv
+	case ErrNotIndexable:
+		errv.Path = append([]interface{}{sel}, errv.Path...)
+		return errv
+	case pathError:
+		errv.path = append([]interface{}{sel}, errv.path...)
+		return errv
+	case Errors:
+		// the errors of a partial result
+		ret := make(Errors, len(errv))
+		for i, err := range errv {
+			ret[i] = inPath(err, sel)
+		}
+
+		return ret
+	default:
+		return err
+	}
+}
+
+// partial handles err, from selecting one of the values of a selector which
+// collects many values, with the path of the value. Without the
+// PartialResults option err is returned as is. With it err is added to errs
+// instead, and partial reports whether the value is still to be collected,
+// which it is if it's a partial result itself.
+func (o options) partial(errs *Errors, err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+
+	if !o.partialResults {
+		return false, err
+	}
+
+	if nested, ok := err.(Errors); ok {
+		*errs = append(*errs, nested...)
+		return true, nil
+	}
+
+	*errs = append(*errs, err)
+	return false, nil
+}
+
+// orNil returns errs, or nil if it's empty
+func (errs Errors) orNil() error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
 }
 
 // Select selects a value from a generic object created from passing
 // interface{} into json.Unmarshal. sels have the following semantics:
 //		string - select a value from a map[string]interface obj
 //		[]string - filter a map[string]interface obj to only have the listed keys
-//		int - select a value from a []interface{}
+//		int - select a value from a []interface{}, negative values count
+//			from the end of the array
 //		[]int if len 0 - noop
 //		[]int if len 1 - select [n0:] from a []interface{}
 //		[]int if len 2 - select [n0:n1] from a []interface{}
-// All other combinations return an error
+//		[]int if len 3 - select every n2th element of [n0:n1] from a
+//			[]interface{}, a negative n2 walks backwards from the end
+//		Star - select every value of a map[string]interface{} or every
+//			element of a []interface{}, see Wildcard
+//		Descend - select every value under a key at any depth
+//		Where - filter a []interface{} with a predicate
+//		Values - select the listed keys of a map[string]interface{} into
+//			a []interface{}, in order
+//		KeyMatch - filter a map[string]interface{} to the keys matching a
+//			regular expression
+//		KeyPrefix, KeySuffix - filter a map[string]interface{} to the keys
+//			starting or ending with a string
+//		Projection - select the listed keys of a map[string]interface{}, or
+//			the listed elements of a []interface{}
+//		ClampRange - select a range of a []interface{} like a 2 element
+//			[]int, with the bounds clamped to the array
+//		Coalesce - replace a null value with a fallback
+//		Unescape - decode a string holding a JSON document
+//		Func - replace the value with the result of a function
+// Negative bounds in an []int are normalized against the array length, so
+// []int{-2} selects the last two elements and []int{0, -1} selects all but
+// the last element. An end at or before the start selects no elements, and
+// a bound which is out of range even after normalizing is reported as an
+// ErrKeyNotPresent with the bounds as they were passed in.
+//
+// Values of other Go types are selected from using reflection, after
+// following pointers and interfaces: a string selects a field of a struct
+// or a value of a map with string keys, and an int selects an element of a
+// slice or array, negative values count from the end. A string matches a
+// struct field by the name in its json tag, falling back to the Go name of
+// fields without one. Like encoding/json a field renamed by its tag is not
+// matched by its Go name, fields tagged "-" and unexported fields are never
+// matched, and the fields of embedded structs are promoted. The selected
+// value is converted back to an interface{} and the rest of the selectors
+// are applied to it.
+//
+// All other combinations return an error. A selector of a type not listed
+// above, or a malformed []int, is an ErrInvalidSelector.
 func Select(obj interface{}, sels ...interface{}) (interface{}, error) {
+	return options{}.selectRoot(obj, sels)
+}
+
+// options change how selectors are applied, the zero value is the behavior
+// of Select
+type options struct {
+	// fold matches string selectors against object keys ignoring case when
+	// there's no exact match
+	fold bool
+	// nullMissing treats keys and elements with a null value as missing
+	nullMissing bool
+	// intKeys selects the key of the same digits for an int selector
+	// applied to an object
+	intKeys bool
+	// maxDepth limits the depth of traversals, 0 is unlimited
+	maxDepth int
+	// strictInts rejects float64s which aren't an int in SelectInt
+	strictInts bool
+	// floatIndexes converts integral float64 selectors into ints
+	floatIndexes bool
+	// partialResults collects errors instead of failing in the selectors
+	// which collect many values
+	partialResults bool
+	// ctx is checked for cancellation while selecting, it's nil unless
+	// selecting with SelectContext
+	ctx *ctxCheck
+}
+
+// selectRoot is selectValue for the full list of selectors of a selection,
+// which are validated before any are applied.
+func (o options) selectRoot(obj interface{}, sels []interface{}) (interface{}, error) {
+	if o.floatIndexes {
+		sels = floatIndexes(sels)
+	}
+
+	err := validateSelectors(sels)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.selectValue(obj, sels)
+}
 
+func (o options) selectValue(obj interface{}, sels []interface{}) (interface{}, error) {
 	if len(sels) == 0 {
 		return obj, nil
 	}
 
-	var err error
+	err := o.ctx.check()
+	if err != nil {
+		return nil, err
+	}
+
+	// selectors which apply to any kind of value
+	switch sel := sels[0].(type) {
+	case Descend:
+		return o.selectDescend(obj, sel, sels[1:])
+	case Projection:
+		return o.selectProjection(obj, sel, sels[1:])
+	case Coalesce:
+		if obj == nil {
+			obj = sel.Fallback
+		}
+
+		return o.selectValue(obj, sels[1:])
+	case Unescape:
+		return o.selectUnescape(obj, sels[1:])
+	case Func:
+		return o.selectFunc(obj, sel, sels[1:])
+	case func(interface{}) (interface{}, error):
+		return o.selectFunc(obj, sel, sels[1:])
+	}
+
+	if obj == nil {
+		return nil, ErrNotIndexable{Selector: sels[0]}
+	}
 
 	switch objv := obj.(type) {
+	case *OrderedMap:
+		return o.selectOrdered(objv, sels)
+
 	case map[string]interface{}:
 		switch sel := sels[0].(type) {
 		case string:
-			v, ok := objv[sel]
-			if !ok {
-				return nil, ErrKeyNotPresent{sel}
+			return o.selectKey(objv, sel, sels[1:])
+
+		case int:
+			if !o.intKeys {
+				return nil, fmt.Errorf("cannot index object with %d", sel)
 			}
 
-			return Select(v, sels[1:]...)
+			return o.selectKey(objv, strconv.Itoa(sel), sels[1:])
 
 		case []string:
+			var errs Errors
 			ret := map[string]interface{}{}
 			for _, seli := range sel {
-				v, ok := objv[seli]
+				v, ok, err := o.key(objv, seli)
+				if err != nil {
+					return nil, err
+				}
+
 				if !ok {
-					return nil, ErrKeyNotPresent{sel}
+					_, err := o.partial(&errs, o.keyNotPresent(objv, seli))
+					if err != nil {
+						return nil, err
+					}
+
+					continue
 				}
 
-				ret[seli], err = Select(v, sels[1:]...)
+				v, err = o.selectValue(v, sels[1:])
+				keep, err := o.partial(&errs, inPath(err, seli))
 				if err != nil {
 					return nil, err
 				}
+
+				if keep {
+					ret[seli] = v
+				}
 			}
 
-			return ret, nil
+			return ret, errs.orNil()
+
+		case Star:
+			return o.selectEachValue(objv, sels[1:])
+
+		case Values:
+			return o.selectValues(objv, sel, sels[1:])
+
+		case KeyMatch:
+			return o.selectMatching(objv, sel.Re.MatchString, sels[1:])
+
+		case KeyPrefix:
+			return o.selectMatching(objv, sel.match, sels[1:])
+
+		case KeySuffix:
+			return o.selectMatching(objv, sel.match, sels[1:])
 
 		default:
-			return nil, fmt.Errorf("cannot index object with %q", sels[0])
+			return nil, fmt.Errorf("cannot index object with %v", sels[0])
 		}
 
 	case []interface{}:
 
 		switch sel := sels[0].(type) {
 		case int:
-			if sel < 0 || sel >= len(objv) {
-				return nil, ErrKeyNotPresent{[]int{sel, len(objv)}}
+			v, idx, err := o.index(objv, sel)
+			if err != nil {
+				return nil, err
 			}
 
-			return Select(objv[sel], sels[1:]...)
+			v, err = o.selectValue(v, sels[1:])
+			return v, inPath(err, idx)
 
 		case []int:
-			start := 0
-			end := len(objv)
-
-			switch len(sel) {
-			case 2:
-				end = sel[1]
-				fallthrough
-			case 1:
-				start = sel[0]
-			case 0:
-				// no op
-			default:
-				//len(sel) > 2
-				return nil, fmt.Errorf("slice selector can have a max of 2 elements")
-			}
-
-			if start < 0 || start > len(objv) {
-				return nil, ErrKeyNotPresent{append(sel, len(objv))}
-			}
-
-			if end < 0 || end > len(objv) {
-				return nil, ErrKeyNotPresent{append(sel, len(objv))}
+			start, end, step, err := sliceBounds(sel, len(objv))
+			if err != nil {
+				return nil, err
 			}
 
-			ret := make([]interface{}, end-start)
-			for i, v := range objv[start:end] {
-				ret[i], err = Select(v, sels[1:]...)
+			var errs Errors
+			elems := sliceElems(objv, start, end, step)
+			ret := make([]interface{}, 0, len(elems))
+			for i, v := range elems {
+				v, err := o.selectValue(v, sels[1:])
+				keep, err := o.partial(&errs, inPath(err, sliceIndex(start, end, step, i)))
 				if err != nil {
 					return nil, err
 				}
+
+				if keep {
+					ret = append(ret, v)
+				}
 			}
 
-			return ret, nil
+			return ret, errs.orNil()
+
+		case Star:
+			return o.selectEachElem(objv, sels[1:])
+
+		case Where:
+			return o.selectWhere(objv, sel, sels[1:])
+
+		case ClampRange:
+			return o.selectClamp(objv, sel, sels[1:])
 
 		default:
 			return nil, fmt.Errorf("cannot index array with %q", sels[0])
 		}
 
 	default:
+		v, ok, err := o.selectReflect(obj, sels)
+		if ok {
+			return v, err
+		}
+
 		// the object we are selecting from is not a composite type
-		return nil, fmt.Errorf("cannot select field %v of %v", sels[0], obj)
+		return nil, ErrNotIndexable{Value: obj, Selector: sels[0]}
+	}
+}
+
+// key looks up key in obj. When o.fold is set and there's no exact match
+// the keys are compared with strings.EqualFold, if more than one of them
+// matches an error is returned rather than picking one arbitrarily. When
+// o.nullMissing is set a null value is reported as missing.
+func (o options) key(obj map[string]interface{}, key string) (interface{}, bool, error) {
+	v, ok := obj[key]
+	if ok && o.nullMissing && v == nil {
+		return nil, false, nil
+	}
+
+	if ok || !o.fold {
+		return v, ok, nil
+	}
+
+	var matches []string
+	for k := range obj {
+		if strings.EqualFold(k, key) {
+			matches = append(matches, k)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, false, nil
+	case 1:
+		v = obj[matches[0]]
+		if o.nullMissing && v == nil {
+			return nil, false, nil
+		}
+
+		return v, true, nil
+	default:
+		sort.Strings(matches)
+		return nil, false, fmt.Errorf("key %q ambiguously matches %q ignoring case",
+			key, matches)
+	}
+}
+
+// checkDepth returns an ErrMaxDepth if depth is more than o.maxDepth
+func (o options) checkDepth(depth int) error {
+	if o.maxDepth > 0 && depth > o.maxDepth {
+		return fmt.Errorf("%w, the limit is %d", ErrMaxDepth, o.maxDepth)
+	}
+
+	return nil
+}
+
+// selectKey selects key from obj and then applies sels to its value
+func (o options) selectKey(obj map[string]interface{}, key string, sels []interface{}) (interface{}, error) {
+	v, ok, err := o.key(obj, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, o.keyNotPresent(obj, key)
+	}
+
+	v, err = o.selectValue(v, sels)
+	return v, inPath(err, key)
+}
+
+// index returns the element of obj at idx, negative values counting from the
+// end of the array, along with the normalized index
+func (o options) index(obj []interface{}, idx int) (interface{}, int, error) {
+	i := idx
+	if i < 0 {
+		i += len(obj)
+	}
+
+	if i < 0 || i >= len(obj) {
+		return nil, 0, ErrKeyNotPresent{Key: []int{idx, len(obj)}}
+	}
+
+	if o.nullMissing && obj[i] == nil {
+		return nil, 0, ErrKeyNotPresent{
+			Key:  []int{idx, len(obj)},
+			null: true,
+		}
+	}
+
+	return obj[i], i, nil
+}
+
+// keyNotPresent returns the error for key missing from obj
+func (o options) keyNotPresent(obj map[string]interface{}, key string) error {
+	v, ok := obj[key]
+	return ErrKeyNotPresent{
+		Key:  key,
+		null: o.nullMissing && ok && v == nil,
+	}
+}
+
+// lookup is like Select but reports a miss, or an invalid selector, with a
+// bool instead of an error.
+// Simple string and int selectors on a map[string]interface{} or
+// []interface{} are followed directly, and fail without an error when they
+// don't apply to the value, such as a string selector on an array or any
+// selector on a number. Anything else, such as an *OrderedMap, a value
+// selected with reflection or the other selectors (or any string selector
+// when o.fold is set), falls back to selectValue for the rest of the chain.
+func (o options) lookup(obj interface{}, sels []interface{}) (interface{}, bool) {
+	if o.floatIndexes {
+		sels = floatIndexes(sels)
+	}
+
+	if validateSelectors(sels) != nil {
+		return nil, false
+	}
+
+	for i, sel := range sels {
+		switch sel := sel.(type) {
+		case string:
+			if scalar(obj) {
+				return nil, false
+			}
+
+			if o.fold {
+				v, err := o.selectValue(obj, sels[i:])
+				return v, err == nil
+			}
+
+			objv, ok := obj.(map[string]interface{})
+			if _, isArr := obj.([]interface{}); isArr {
+				return nil, false
+			}
+
+			if !ok {
+				v, err := o.selectValue(obj, sels[i:])
+				return v, err == nil
+			}
+
+			obj, ok = objv[sel]
+			if !ok || (o.nullMissing && obj == nil) {
+				return nil, false
+			}
+
+		case int:
+			objv, ok := obj.([]interface{})
+			if _, isMap := obj.(map[string]interface{}); scalar(obj) || (isMap && !o.intKeys) {
+				return nil, false
+			}
+
+			if !ok {
+				v, err := o.selectValue(obj, sels[i:])
+				return v, err == nil
+			}
+
+			if sel < 0 {
+				sel += len(objv)
+			}
+
+			if sel < 0 || sel >= len(objv) {
+				return nil, false
+			}
+
+			obj = objv[sel]
+			if o.nullMissing && obj == nil {
+				return nil, false
+			}
+
+		default:
+			v, err := o.selectValue(obj, sels[i:])
+			return v, err == nil
+		}
+	}
+
+	return obj, true
+}
+
+// scalar reports whether v is a JSON value which no string or int selector
+// can select from
+func scalar(v interface{}) bool {
+	switch kindOf(v) {
+	case KindNull, KindBool, KindNumber, KindString:
+		return true
+	default:
+		return false
+	}
+}
+
+// floatIndexes returns sels with the integral float64 selectors replaced by
+// ints, sels itself is only copied if there are any.
+func floatIndexes(sels []interface{}) []interface{} {
+	var ret []interface{}
+	for i, sel := range sels {
+		f, ok := sel.(float64)
+		if !ok || f != math.Trunc(f) || f < math.MinInt || f >= -math.MinInt {
+			continue
+		}
+
+		if ret == nil {
+			ret = append([]interface{}{}, sels...)
+		}
+
+		ret[i] = int(f)
+	}
+
+	if ret == nil {
+		return sels
+	}
+
+	return ret
+}
+
+// sliceBounds converts an []int slice selector into bounds for an array of
+// length n. Negative bounds count back from the end of the array, the error
+// still reports the selector as it was passed in.
+func sliceBounds(sel []int, n int) (start, end, step int, err error) {
+	start = 0
+	end = n
+	step = 1
+
+	switch len(sel) {
+	case 3:
+		step = sel[2]
+		if step == 0 {
+			return 0, 0, 0, fmt.Errorf("slice selector %v cannot have a step of 0", sel)
+		}
+		fallthrough
+	case 2:
+		end = sel[1]
+		if end < 0 {
+			end += n
+		}
+		fallthrough
+	case 1:
+		start = sel[0]
+	case 0:
+		// no op
+	default:
+		//len(sel) > 3
+		return 0, 0, 0, fmt.Errorf("slice selector can have a max of 3 elements")
+	}
+
+	if start < 0 {
+		start += n
+	}
+
+	if start < 0 || start > n {
+		return 0, 0, 0, ErrKeyNotPresent{Key: append(sel[:len(sel):len(sel)], n)}
+	}
+
+	if end < 0 || end > n {
+		return 0, 0, 0, ErrKeyNotPresent{Key: append(sel[:len(sel):len(sel)], n)}
+	}
+
+	return start, end, step, nil
+}
+
+// sliceElems returns the elements of obj selected by bounds from sliceBounds.
+// A negative step walks [start:end] backwards, starting from the last element.
+func sliceElems(obj []interface{}, start, end, step int) []interface{} {
+	if end <= start {
+		return []interface{}{}
+	}
+
+	if step == 1 {
+		return obj[start:end]
+	}
+
+	ret := make([]interface{}, 0, sliceLen(start, end, step))
+	if step > 0 {
+		for i := start; i < end; i += step {
+			ret = append(ret, obj[i])
+		}
+	} else {
+		for i := end - 1; i >= start; i += step {
+			ret = append(ret, obj[i])
+		}
+	}
+
+	return ret
+}
+
+// sliceIndex returns the index into the array of the ith element selected by
+// bounds from sliceBounds
+func sliceIndex(start, end, step, i int) int {
+	if step < 0 {
+		return end - 1 + i*step
+	}
+
+	return start + i*step
+}
+
+// sliceLen returns the number of elements selected by bounds from sliceBounds
+func sliceLen(start, end, step int) int {
+	if end <= start {
+		return 0
+	}
+
+	if step < 0 {
+		step = -step
 	}
+
+	return (end - start + step - 1) / step
 }
@@ -0,0 +1,391 @@
+package json_select
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// benchDoc is a document with an array of objects, for the benchmarks
+func benchDoc(b *testing.B, n int) Selecter {
+	b.Helper()
+
+	items := make([]interface{}, n)
+	for i := range items {
+		items[i] = map[string]interface{}{
+			"name":  "item",
+			"price": float64(i),
+		}
+	}
+
+	return Selecter{V: map[string]interface{}{"items": items}}
+}
+
+func TestSelectSliceEmpty(t *testing.T) {
+	j, err := FromJSON([]byte(`{"a":[]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slc, err := j.SelectSlice("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if slc == nil {
+		t.Fatal("SelectSlice returned a nil slice for an empty array")
+	}
+
+	data, err := json.Marshal(slc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "[]" {
+		t.Fatalf("got %s, want []", data)
+	}
+
+	dst := make([]Selecter, 0, 4)
+	slc, err = j.SelectSliceInto(dst, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if slc == nil || len(slc) != 0 {
+		t.Fatalf("got %#v, want an empty slice", slc)
+	}
+}
+
+func BenchmarkSelectSlice(b *testing.B) {
+	j := benchDoc(b, 100)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := j.SelectSlice("items")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSelectSliceInto(b *testing.B) {
+	j := benchDoc(b, 100)
+	var dst []Selecter
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var err error
+		dst, err = j.SelectSliceInto(dst, "items")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestFieldIndexMatchSelect(t *testing.T) {
+	j, err := FromJSON([]byte(`{"a":[1,2,3],"n":null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	arr, err := j.Field("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, i := range []int{0, 2, -1, 3, -4} {
+		got, gotErr := arr.Index(i)
+		want, wantErr := arr.opts.selectRoot(arr.V, []interface{}{i})
+		if !equal(got.V, want) || errString(gotErr) != errString(wantErr) {
+			t.Errorf("Index(%d) = %v, %v; want %v, %v", i, got, gotErr, want, wantErr)
+		}
+	}
+
+	for _, k := range []string{"a", "n", "missing"} {
+		got, gotErr := j.Field(k)
+		want, wantErr := j.opts.selectRoot(j.V, []interface{}{k})
+		if !equal(got.V, want) || errString(gotErr) != errString(wantErr) {
+			t.Errorf("Field(%q) = %v, %v; want %v, %v", k, got, gotErr, want, wantErr)
+		}
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+func BenchmarkField(b *testing.B) {
+	j := benchDoc(b, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := j.Field("items")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFieldSelect(b *testing.B) {
+	j := benchDoc(b, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := j.opts.selectRoot(j.V, []interface{}{"items"})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIndex(b *testing.B) {
+	arr, _ := benchDoc(b, 10).Field("items")
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := arr.Index(5)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIndexSelect(b *testing.B) {
+	arr, _ := benchDoc(b, 10).Field("items")
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := arr.opts.selectRoot(arr.V, []interface{}{5})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSelectOneKey(b *testing.B) {
+	j := benchDoc(b, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := j.Select("items")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestErrorsIsAs(t *testing.T) {
+	coercion := ErrCoercion{Value: "a", Type: "string", Target: "int"}
+	errs := Errors{
+		fmt.Errorf("first: %w", ErrKeyNotPresent{Key: "a"}),
+		coercion,
+	}
+
+	if !errs.Is(ErrKeyNotPresent{}) || !errors.Is(errs, ErrKeyNotPresent{}) {
+		t.Error("Errors does not match ErrKeyNotPresent")
+	}
+
+	if errs.Is(ErrMaxDepth) {
+		t.Error("Errors matches ErrMaxDepth")
+	}
+
+	var target ErrCoercion
+	if !errs.As(&target) || target != coercion {
+		t.Errorf("Errors.As = %v, want %v", target, coercion)
+	}
+
+	var notIndexable ErrNotIndexable
+	if errs.As(&notIndexable) {
+		t.Error("Errors.As matches ErrNotIndexable")
+	}
+}
+
+func TestSelectJSONNumber(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(
+		`{"big": 9007199254740993, "neg": -9007199254740993, "whole": 3.0, "half": 2.5}`))
+	dec.UseNumber()
+
+	var v interface{}
+	err := dec.Decode(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j := Selecter{V: v}
+
+	i64, err := j.SelectInt64("big")
+	if err != nil || i64 != 1<<53+1 {
+		t.Errorf("SelectInt64(big) = %v, %v, want %v", i64, err, 1<<53+1)
+	}
+
+	i64, err = j.SelectInt64("neg")
+	if err != nil || i64 != -(1<<53+1) {
+		t.Errorf("SelectInt64(neg) = %v, %v, want %v", i64, err, -(1<<53 + 1))
+	}
+
+	u64, err := j.SelectUint64("big")
+	if err != nil || u64 != 1<<53+1 {
+		t.Errorf("SelectUint64(big) = %v, %v, want %v", u64, err, 1<<53+1)
+	}
+
+	// fractional numbers are converted as a float64
+	i64, err = j.SelectInt64("whole")
+	if err != nil || i64 != 3 {
+		t.Errorf("SelectInt64(whole) = %v, %v, want 3", i64, err)
+	}
+
+	u64, err = j.SelectUint64("whole")
+	if err != nil || u64 != 3 {
+		t.Errorf("SelectUint64(whole) = %v, %v, want 3", u64, err)
+	}
+
+	i, err := j.SelectInt("half")
+	if err != nil || i != 2 {
+		t.Errorf("SelectInt(half) = %v, %v, want 2", i, err)
+	}
+
+	f, err := j.SelectFloat("half")
+	if err != nil || f != 2.5 {
+		t.Errorf("SelectFloat(half) = %v, %v, want 2.5", f, err)
+	}
+
+	_, err = j.SelectInt64("half")
+	if !errors.As(err, &ErrCoercion{}) {
+		t.Errorf("SelectInt64(half) = %v, want ErrCoercion", err)
+	}
+
+	_, err = j.SelectUint64("neg")
+	if !errors.As(err, &ErrCoercion{}) {
+		t.Errorf("SelectUint64(neg) = %v, want ErrCoercion", err)
+	}
+
+	bad := Selecter{V: map[string]interface{}{"n": json.Number("12abc")}}
+
+	_, err = bad.SelectInt("n")
+	if !errors.As(err, &ErrCoercion{}) {
+		t.Errorf("SelectInt(12abc) = %v, want ErrCoercion", err)
+	}
+
+	_, err = bad.SelectInt64("n")
+	if !errors.As(err, &ErrCoercion{}) {
+		t.Errorf("SelectInt64(12abc) = %v, want ErrCoercion", err)
+	}
+
+	_, err = bad.SelectUint64("n")
+	if !errors.As(err, &ErrCoercion{}) {
+		t.Errorf("SelectUint64(12abc) = %v, want ErrCoercion", err)
+	}
+
+	_, err = bad.SelectFloat("n")
+	if !errors.As(err, &ErrCoercion{}) {
+		t.Errorf("SelectFloat(12abc) = %v, want ErrCoercion", err)
+	}
+}
+
+func TestSelectIntExact(t *testing.T) {
+	for _, tc := range []struct {
+		v     interface{}
+		n     int
+		exact bool
+	}{
+		{2.0, 2, true},
+		{2.5, 2, false},
+		{-2.5, -2, false},
+		{1e300, math.MaxInt, false},
+		{-1e300, math.MinInt, false},
+		{json.Number("2"), 2, true},
+		{json.Number("2.5"), 2, false},
+		{json.Number("1e300"), math.MaxInt, false},
+		{json.Number("-1e300"), math.MinInt, false},
+		{json.Number("1e400"), math.MaxInt, false},
+		{"99999999999999999999", math.MaxInt, false},
+		{"-99999999999999999999", math.MinInt, false},
+	} {
+		n, exact, err := Selecter{V: tc.v}.SelectIntExact()
+		if n != tc.n || exact != tc.exact || err != nil {
+			t.Errorf("SelectIntExact(%v) = %v, %v, %v, want %v, %v, nil",
+				tc.v, n, exact, err, tc.n, tc.exact)
+		}
+	}
+
+	_, _, err := Selecter{V: math.NaN()}.SelectIntExact()
+	if !errors.As(err, &ErrCoercion{}) {
+		t.Errorf("SelectIntExact(NaN) = %v, want ErrCoercion", err)
+	}
+}
+
+func TestExistsNoAllocs(t *testing.T) {
+	j, err := FromJSON([]byte(`{"a":[1,{"b":"x"}],"n":null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		sels []interface{}
+		want bool
+	}{
+		{[]interface{}{"a", 1, "b"}, true},
+		{[]interface{}{"missing"}, false},
+		{[]interface{}{"a", "b"}, false},
+		{[]interface{}{"a", 0, "b"}, false},
+		{[]interface{}{"a", 1, "b", 0}, false},
+		{[]interface{}{"n", "b"}, false},
+		{[]interface{}{0}, false},
+	} {
+		sels := tc.sels
+		if got := j.Exists(sels...); got != tc.want {
+			t.Errorf("Exists(%v) = %v, want %v", sels, got, tc.want)
+		}
+
+		allocs := testing.AllocsPerRun(100, func() {
+			j.Exists(sels...)
+		})
+		if allocs != 0 {
+			t.Errorf("Exists(%v) allocated %v times", sels, allocs)
+		}
+	}
+}
+
+func TestCompositeCoercionErrors(t *testing.T) {
+	j, err := FromJSON([]byte(`{"arr":[1],"obj":{"a":"b"},"n":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		target string
+		fn     func() error
+	}{
+		{"SelectSlice", "[]interface{}", func() error { _, err := j.SelectSlice("obj"); return err }},
+		{"SelectSliceInto", "[]interface{}", func() error { _, err := j.SelectSliceInto(nil, "n"); return err }},
+		{"Contains", "[]interface{}", func() error { _, err := j.Contains(1.0, "obj"); return err }},
+		{"First", "[]interface{}", func() error { _, err := j.First("n"); return err }},
+		{"Last", "[]interface{}", func() error { _, err := j.Last("obj"); return err }},
+		{"SelectMap", "map[string]interface{}", func() error { _, err := j.SelectMap("arr"); return err }},
+		{"SelectMapInto", "map[string]interface{}", func() error { _, err := j.SelectMapInto(nil, "n"); return err }},
+		{"SelectMapString", "map[string]interface{}", func() error { _, err := j.SelectMapString("arr"); return err }},
+		{"SelectMapStringCoerce", "map[string]interface{}", func() error { _, err := j.SelectMapStringCoerce("n"); return err }},
+		{"Keys", "map[string]interface{}", func() error { _, err := j.Keys("arr"); return err }},
+	} {
+		var coercion ErrCoercion
+		err := tc.fn()
+		if !errors.As(err, &coercion) || coercion.Target != tc.target {
+			t.Errorf("%s = %v, want ErrCoercion to %s", tc.name, err, tc.target)
+		}
+	}
+}
@@ -0,0 +1,451 @@
+package json_select
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CompileJSONPath parses a JSONPath expression into selectors and compiles
+// them into a Query, see Query.Nodes for evaluating it the way JSONPath does.
+// The supported subset of JSONPath is:
+//		$ - the root, every expression starts with it
+//		.key - a key made of letters, digits, _ and -
+//		['key'] or ["key"] - a quoted key, \ escapes the next character
+//		['a','b'] - the listed keys, as a Values selector
+//		[n] - an element, negative values count from the end
+//		[n,m] - the listed elements, as a Projection
+//		[start:end] - the elements in the range, as a ClampRange, either
+//			bound may be left out
+//		.* or [*] - every value or element, as a Wildcard
+//		..key - a key at any depth, as a Descend
+//		[?(@.a.b)] - the elements which have a value at the path, as a Where
+//		[?(@.a op lit)] - the elements with a value at the path for which
+//			the comparison holds
+// A filter path is made of .key, ['key'] and [n] segments, and may be empty
+// to compare the element itself. The comparison operators are ==, !=, <,
+// <=, > and >=, and the literal is a number, a quoted string, true, false
+// or null. Values which are not both numbers or both strings are never
+// ordered. Filters only apply to arrays. Anything else, such as a slice
+// with a step, ..* or a function, is an ErrPathSyntax.
+//
+// Unlike JSONPath, a key or element selected outside of a wildcard,
+// descendant, slice or filter must exist, and the keys and elements listed
+// in a union must all exist.
+func CompileJSONPath(expr string) (Query, error) {
+	p := jsonPathParser{pathParser{path: expr}}
+
+	sels, err := p.parse()
+	if err != nil {
+		return Query{}, err
+	}
+
+	return Compile(sels...)
+}
+
+type jsonPathParser struct {
+	pathParser
+}
+
+func (p *jsonPathParser) parse() ([]interface{}, error) {
+	if !strings.HasPrefix(p.path, "$") {
+		return nil, p.errorf(0, "expected $ at the start of the expression")
+	}
+
+	p.pos++
+
+	sels := []interface{}{}
+	for p.pos < len(p.path) {
+		switch {
+		case strings.HasPrefix(p.path[p.pos:], ".."):
+			p.pos += 2
+			key := p.name()
+			if key == "" {
+				return nil, p.errorf(p.pos, "expected a key after ..")
+			}
+
+			sels = append(sels, Descend{Key: key})
+
+		case strings.HasPrefix(p.path[p.pos:], ".*"):
+			p.pos += 2
+			sels = append(sels, Wildcard)
+
+		case p.path[p.pos] == '.':
+			p.pos++
+			key := p.name()
+			if key == "" {
+				return nil, p.errorf(p.pos, "expected a key after .")
+			}
+
+			sels = append(sels, key)
+
+		case p.path[p.pos] == '[':
+			sel, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+
+			sels = append(sels, sel)
+
+		default:
+			return nil, p.errorf(p.pos, "expected . or [")
+		}
+	}
+
+	return sels, nil
+}
+
+// name consumes a bare key
+func (p *jsonPathParser) name() string {
+	start := p.pos
+	for p.pos < len(p.path) {
+		c := p.path[p.pos]
+		if !(c == '_' || c == '-' || c >= 0x80 ||
+			('0' <= c && c <= '9') ||
+			('a' <= c && c <= 'z') ||
+			('A' <= c && c <= 'Z')) {
+			break
+		}
+
+		p.pos++
+	}
+
+	return p.path[start:p.pos]
+}
+
+// bracket consumes a bracketed selector, starting at the [
+func (p *jsonPathParser) bracket() (interface{}, error) {
+	open := p.pos
+	p.pos++
+	p.space()
+
+	var sel interface{}
+	var err error
+
+	switch {
+	case p.pos >= len(p.path):
+		return nil, p.errorf(open, "unbalanced [")
+	case p.path[p.pos] == '*':
+		p.pos++
+		sel = Wildcard
+	case p.path[p.pos] == '\'' || p.path[p.pos] == '"':
+		sel, err = p.keys()
+	case p.path[p.pos] == '?':
+		sel, err = p.filter()
+	default:
+		sel, err = p.indices()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.space()
+	if p.pos >= len(p.path) || p.path[p.pos] != ']' {
+		return nil, p.errorf(open, "unbalanced [")
+	}
+
+	p.pos++
+	return sel, nil
+}
+
+// keys consumes a comma separated list of quoted keys
+func (p *jsonPathParser) keys() (interface{}, error) {
+	var keys []string
+	for {
+		if p.pos >= len(p.path) || (p.path[p.pos] != '\'' && p.path[p.pos] != '"') {
+			return nil, p.errorf(p.pos, "expected a quoted key")
+		}
+
+		key, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+
+		p.space()
+		if p.pos >= len(p.path) || p.path[p.pos] != ',' {
+			break
+		}
+
+		p.pos++
+		p.space()
+	}
+
+	if len(keys) == 1 {
+		return keys[0], nil
+	}
+
+	return Values{Keys: keys}, nil
+}
+
+// indices consumes an index, a comma separated list of indices, or a slice
+func (p *jsonPathParser) indices() (interface{}, error) {
+	start, hasStart, err := p.int()
+	if err != nil {
+		return nil, err
+	}
+
+	p.space()
+	if p.pos < len(p.path) && p.path[p.pos] == ':' {
+		p.pos++
+		p.space()
+
+		end, hasEnd, err := p.int()
+		if err != nil {
+			return nil, err
+		}
+
+		p.space()
+		if p.pos < len(p.path) && p.path[p.pos] == ':' {
+			return nil, p.errorf(p.pos, "slices with a step are not supported")
+		}
+
+		if !hasEnd {
+			end = math.MaxInt
+		}
+
+		return ClampRange{Start: start, End: end}, nil
+	}
+
+	if !hasStart {
+		return nil, p.errorf(p.pos, "expected an index")
+	}
+
+	if p.pos >= len(p.path) || p.path[p.pos] != ',' {
+		return start, nil
+	}
+
+	sel := Projection{start}
+	for p.pos < len(p.path) && p.path[p.pos] == ',' {
+		p.pos++
+		p.space()
+
+		idx, ok, err := p.int()
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return nil, p.errorf(p.pos, "expected an index")
+		}
+
+		sel = append(sel, idx)
+		p.space()
+	}
+
+	return sel, nil
+}
+
+// int consumes an optional integer, reporting whether there was one
+func (p *jsonPathParser) int() (int, bool, error) {
+	start := p.pos
+	if p.pos < len(p.path) && p.path[p.pos] == '-' {
+		p.pos++
+	}
+
+	for p.pos < len(p.path) && '0' <= p.path[p.pos] && p.path[p.pos] <= '9' {
+		p.pos++
+	}
+
+	if p.pos == start {
+		return 0, false, nil
+	}
+
+	i, err := strconv.Atoi(p.path[start:p.pos])
+	if err != nil {
+		return 0, false, p.errorf(start, "index %q is not an integer",
+			p.path[start:p.pos])
+	}
+
+	return i, true, nil
+}
+
+// filter consumes a filter expression, starting at the ?
+func (p *jsonPathParser) filter() (interface{}, error) {
+	open := p.pos
+	if !strings.HasPrefix(p.path[p.pos:], "?(") {
+		return nil, p.errorf(p.pos, "expected ( after ?")
+	}
+
+	p.pos += 2
+	p.space()
+	if p.pos >= len(p.path) || p.path[p.pos] != '@' {
+		return nil, p.errorf(p.pos, "expected @ at the start of the filter")
+	}
+
+	p.pos++
+	sels, err := p.filterPath()
+	if err != nil {
+		return nil, err
+	}
+
+	p.space()
+	op := ""
+	for _, o := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(p.path[p.pos:], o) {
+			op = o
+			break
+		}
+	}
+
+	var lit interface{}
+	if op != "" {
+		p.pos += len(op)
+		p.space()
+
+		lit, err = p.literal()
+		if err != nil {
+			return nil, err
+		}
+
+		p.space()
+	}
+
+	if p.pos >= len(p.path) || p.path[p.pos] != ')' {
+		return nil, p.errorf(open, "unterminated filter")
+	}
+
+	p.pos++
+	return jsonPathFilter(sels, op, lit), nil
+}
+
+// filterPath consumes the path after the @ of a filter
+func (p *jsonPathParser) filterPath() ([]interface{}, error) {
+	sels := []interface{}{}
+	for p.pos < len(p.path) {
+		switch p.path[p.pos] {
+		case '.':
+			p.pos++
+			key := p.name()
+			if key == "" {
+				return nil, p.errorf(p.pos, "expected a key after .")
+			}
+
+			sels = append(sels, key)
+
+		case '[':
+			open := p.pos
+			p.pos++
+			p.space()
+
+			var sel interface{}
+			if p.pos < len(p.path) && (p.path[p.pos] == '\'' || p.path[p.pos] == '"') {
+				key, err := p.quoted()
+				if err != nil {
+					return nil, err
+				}
+
+				sel = key
+			} else {
+				idx, ok, err := p.int()
+				if err != nil {
+					return nil, err
+				}
+
+				if !ok {
+					return nil, p.errorf(p.pos, "expected an index or quoted key")
+				}
+
+				sel = idx
+			}
+
+			p.space()
+			if p.pos >= len(p.path) || p.path[p.pos] != ']' {
+				return nil, p.errorf(open, "unbalanced [")
+			}
+
+			p.pos++
+			sels = append(sels, sel)
+
+		default:
+			return sels, nil
+		}
+	}
+
+	return sels, nil
+}
+
+// literal consumes the literal of a filter comparison
+func (p *jsonPathParser) literal() (interface{}, error) {
+	if p.pos < len(p.path) && (p.path[p.pos] == '\'' || p.path[p.pos] == '"') {
+		return p.quoted()
+	}
+
+	for _, kw := range []struct {
+		s string
+		v interface{}
+	}{{"true", true}, {"false", false}, {"null", nil}} {
+		if strings.HasPrefix(p.path[p.pos:], kw.s) {
+			p.pos += len(kw.s)
+			return kw.v, nil
+		}
+	}
+
+	start := p.pos
+	for p.pos < len(p.path) && strings.IndexByte("+-.0123456789eE", p.path[p.pos]) >= 0 {
+		p.pos++
+	}
+
+	f, err := strconv.ParseFloat(p.path[start:p.pos], 64)
+	if err != nil {
+		return nil, p.errorf(start, "expected a number, string, true, false or null")
+	}
+
+	return f, nil
+}
+
+func (p *jsonPathParser) space() {
+	for p.pos < len(p.path) && p.path[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// jsonPathFilter returns the predicate of a filter which compares the value
+// at sels with lit using op, or checks that there is a value if op is empty.
+func jsonPathFilter(sels []interface{}, op string, lit interface{}) Where {
+	return func(elem Selecter) bool {
+		v, ok := elem.Raw(sels...)
+		if !ok {
+			return false
+		}
+
+		switch op {
+		case "":
+			return true
+		case "==":
+			return equal(v, lit)
+		case "!=":
+			return !equal(v, lit)
+		}
+
+		var cmp int
+		af, aok := toFloat(v)
+		bf, bok := toFloat(lit)
+		as, asok := v.(string)
+		bs, bsok := lit.(string)
+
+		switch {
+		case aok && bok && af < bf, asok && bsok && as < bs:
+			cmp = -1
+		case aok && bok && af > bf, asok && bsok && as > bs:
+			cmp = 1
+		case aok && bok && af == bf, asok && bsok && as == bs:
+			cmp = 0
+		default:
+			return false
+		}
+
+		switch op {
+		case "<":
+			return cmp < 0
+		case "<=":
+			return cmp <= 0
+		case ">":
+			return cmp > 0
+		default:
+			return cmp >= 0
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package json_select
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNodesMissingKey(t *testing.T) {
+	j, err := FromJSON([]byte(`{"menu":[{"name":"a"},{"price":1},{"name":"c"},2]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := CompileJSONPath("$.menu[*].name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err := q.Nodes(j)
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("Nodes error = %v, want only the error for the number", err)
+	}
+
+	if !errors.As(errs[0], &ErrNotIndexable{}) {
+		t.Errorf("Nodes error = %v, want ErrNotIndexable", errs[0])
+	}
+
+	got := make([]interface{}, len(nodes))
+	for i, n := range nodes {
+		got[i] = n.V
+	}
+
+	want := []interface{}{"a", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Nodes = %v, want %v", got, want)
+	}
+
+	j, err = FromJSON([]byte(`{"menu":[{"name":"a"},{"price":1}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err = q.Nodes(j)
+	if err != nil || len(nodes) != 1 {
+		t.Errorf("Nodes = %v, %v, want [a], nil", nodes, err)
+	}
+}
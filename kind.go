@@ -0,0 +1,137 @@
+package json_select
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Kind is the JSON type of a value
+type Kind int
+
+const (
+	// KindInvalid is not a JSON type, it's the Kind of values which can't
+	// be represented in JSON
+	KindInvalid Kind = iota
+	KindNull
+	KindBool
+	KindNumber
+	KindString
+	KindArray
+	KindObject
+)
+
+var kindNames = [...]string{
+	KindInvalid: "invalid",
+	KindNull:    "null",
+	KindBool:    "bool",
+	KindNumber:  "number",
+	KindString:  "string",
+	KindArray:   "array",
+	KindObject:  "object",
+}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+
+	return kindNames[k]
+}
+
+// Kind is like Select but returns the JSON type of the selection. The types
+// created by json.Unmarshal map to kinds as follows:
+//		nil - KindNull
+//		bool - KindBool
+//		float64 - KindNumber (as do the other Go numeric types)
+//		string - KindString
+//		[]interface{} - KindArray
+//		map[string]interface{} - KindObject (as does *OrderedMap)
+// An error is returned, along with KindInvalid, for any other type.
+func (j Selecter) Kind(sels ...interface{}) (Kind, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return KindInvalid, err
+	}
+
+	k := kindOf(v)
+	if k == KindInvalid {
+		return KindInvalid, fmt.Errorf("%v (%T) not a JSON value", v, v)
+	}
+
+	return k, nil
+}
+
+func kindOf(v interface{}) Kind {
+	switch vv := v.(type) {
+	case nil:
+		return KindNull
+	case bool:
+		return KindBool
+	case string:
+		return KindString
+	case []interface{}:
+		return KindArray
+	case map[string]interface{}, *OrderedMap:
+		return KindObject
+	case json.Number:
+		// a number out of the range of a float64, such as 1e400, is
+		// still a JSON number
+		if isJSONNumber(string(vv)) {
+			return KindNumber
+		}
+
+		return KindInvalid
+	}
+
+	if _, ok := toFloat(v); ok {
+		return KindNumber
+	}
+
+	return KindInvalid
+}
+
+// isJSONNumber reports whether s is a number in the JSON syntax
+func isJSONNumber(s string) bool {
+	if s != "" && s[0] == '-' {
+		s = s[1:]
+	}
+
+	digits := func() bool {
+		n := 0
+		for n < len(s) && '0' <= s[n] && s[n] <= '9' {
+			n++
+		}
+
+		s = s[n:]
+		return n > 0
+	}
+
+	switch {
+	case s == "":
+		return false
+	case s[0] == '0':
+		s = s[1:]
+	case !digits():
+		return false
+	}
+
+	if s != "" && s[0] == '.' {
+		s = s[1:]
+		if !digits() {
+			return false
+		}
+	}
+
+	if s != "" && (s[0] == 'e' || s[0] == 'E') {
+		s = s[1:]
+		if s != "" && (s[0] == '+' || s[0] == '-') {
+			s = s[1:]
+		}
+
+		if !digits() {
+			return false
+		}
+	}
+
+	return s == ""
+}
@@ -0,0 +1,36 @@
+package json_select
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestKindJSONNumber(t *testing.T) {
+	for _, tc := range []struct {
+		n    json.Number
+		want Kind
+	}{
+		{"1", KindNumber},
+		{"-0.5e-3", KindNumber},
+		{"1e400", KindNumber},
+		{"-1E+400", KindNumber},
+		{"", KindInvalid},
+		{"01", KindInvalid},
+		{"1.", KindInvalid},
+		{"1e", KindInvalid},
+		{"+1", KindInvalid},
+		{"Inf", KindInvalid},
+		{"0x10", KindInvalid},
+	} {
+		j := Selecter{V: tc.n}
+		got, err := j.Kind()
+		if got != tc.want || (err == nil) != (tc.want == KindNumber) {
+			t.Errorf("Kind(%q) = %v, %v, want %v", tc.n, got, err, tc.want)
+		}
+
+		_, err = New(tc.n)
+		if (err == nil) != (tc.want == KindNumber) {
+			t.Errorf("New(%q) = %v", tc.n, err)
+		}
+	}
+}
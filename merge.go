@@ -0,0 +1,103 @@
+package json_select
+
+import (
+	"fmt"
+)
+
+// Merge returns a Selecter for the wrapped value with patch applied, see
+// the Merge function. patch may also be a Selecter.
+func (j Selecter) Merge(patch interface{}) (Selecter, error) {
+	if p, ok := patch.(Selecter); ok {
+		patch = p.V
+	}
+
+	v, err := Merge(j.V, patch)
+	if err != nil {
+		return j, err
+	}
+
+	return j.wrap(v), nil
+}
+
+// Merge applies patch to base using RFC 7386 JSON Merge Patch semantics:
+// if patch is an object each of its keys is merged recursively into base
+// (which is treated as an empty object if it isn't one), with a null value
+// deleting the key, and any other patch replaces base entirely. Neither base
+// nor patch are modified, the result is a new value which shares no maps or
+// arrays with either of them. An *OrderedMap is supported as an object, in
+// base its key order is kept and new keys are added in the order of patch.
+// An error is returned if patch contains a value which is not a JSON type.
+func Merge(base, patch interface{}) (interface{}, error) {
+	switch patchv := patch.(type) {
+	case map[string]interface{}:
+		if om, ok := base.(*OrderedMap); ok {
+			return mergeOrdered(clone(om).(*OrderedMap), sortedKeys(patchv), patchv)
+		}
+
+		basev, _ := base.(map[string]interface{})
+		return mergeMap(clone(basev).(map[string]interface{}), patchv)
+
+	case *OrderedMap:
+		if basev, ok := base.(map[string]interface{}); ok {
+			return mergeMap(clone(basev).(map[string]interface{}), patchv.values)
+		}
+
+		om, ok := base.(*OrderedMap)
+		if !ok {
+			om = NewOrderedMap()
+		}
+
+		return mergeOrdered(clone(om).(*OrderedMap), patchv.keys, patchv.values)
+
+	default:
+		if kindOf(patch) == KindInvalid {
+			return nil, fmt.Errorf("cannot merge %v (%T)", patch, patch)
+		}
+
+		return clone(patch), nil
+	}
+}
+
+// mergeMap merges patch into ret, which is modified
+func mergeMap(ret, patch map[string]interface{}) (interface{}, error) {
+	if ret == nil {
+		ret = make(map[string]interface{}, len(patch))
+	}
+
+	for k, pv := range patch {
+		if pv == nil {
+			delete(ret, k)
+			continue
+		}
+
+		v, err := Merge(ret[k], pv)
+		if err != nil {
+			return nil, err
+		}
+
+		ret[k] = v
+	}
+
+	return ret, nil
+}
+
+// mergeOrdered merges the keys of patch into ret, which is modified, in the
+// order of keys
+func mergeOrdered(ret *OrderedMap, keys []string, patch map[string]interface{}) (interface{}, error) {
+	for _, k := range keys {
+		if patch[k] == nil {
+			ret.Delete(k)
+			continue
+		}
+
+		old, _ := ret.Get(k)
+		v, err := Merge(old, patch[k])
+		if err != nil {
+			return nil, err
+		}
+
+		ret.Set(k, v)
+	}
+
+	return ret, nil
+}
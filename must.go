@@ -0,0 +1,67 @@
+package json_select
+
+// The Must methods are like their Select counterparts but panic if the
+// selection fails, in the same way as regexp.MustCompile. They are meant
+// for tests and for initializing from documents embedded in the program,
+// where a failed selection is a programmer error. Selections from input
+// which may be malformed should use the methods which return an error.
+
+// MustSelect is like Select but panics if the selection fails
+func (j Selecter) MustSelect(sels ...interface{}) Selecter {
+	v, err := j.Select(sels...)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// MustString is like SelectString but panics if the selection fails
+func (j Selecter) MustString(sels ...interface{}) string {
+	v, err := j.SelectString(sels...)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// MustInt is like SelectInt but panics if the selection fails
+func (j Selecter) MustInt(sels ...interface{}) int {
+	v, err := j.SelectInt(sels...)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// MustFloat is like SelectFloat but panics if the selection fails
+func (j Selecter) MustFloat(sels ...interface{}) float64 {
+	v, err := j.SelectFloat(sels...)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// MustBool is like SelectBool but panics if the selection fails
+func (j Selecter) MustBool(sels ...interface{}) bool {
+	v, err := j.SelectBool(sels...)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// MustSlice is like SelectSlice but panics if the selection fails
+func (j Selecter) MustSlice(sels ...interface{}) []Selecter {
+	v, err := j.SelectSlice(sels...)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
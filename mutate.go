@@ -0,0 +1,301 @@
+package json_select
+
+import (
+	"fmt"
+)
+
+// Set returns a Selecter with value assigned at the selection, see the Set
+// function.
+func (j Selecter) Set(value interface{}, sels ...interface{}) (Selecter, error) {
+	v, err := Set(j.V, value, sels...)
+	if err != nil {
+		return j, err
+	}
+
+	return j.wrap(v), nil
+}
+
+// Set assigns value at the location selected by sels within obj and returns
+// the updated obj. Maps and arrays are updated in place, so a document from
+// json.Unmarshal can be re-marshaled after setting values, but the returned
+// obj must be used since appending to an array may reallocate it. sels may
+// only contain the following:
+//		string - set a key in a map[string]interface{}, a missing or null
+//			intermediate value is replaced with a new map
+//		int - set an element of a []interface{}, negative values count from
+//			the end of the array. The element must exist unless the index is
+//			exactly the length of the array, in which case value is appended
+// An empty sels returns value, replacing obj entirely. value is stored as
+// is, so setting an int leaves an int in a document where json.Unmarshal
+// decoded every other number as a float64, see Selecter.NormalizeNumbers
+// for making them consistent.
+func Set(obj interface{}, value interface{}, sels ...interface{}) (interface{}, error) {
+	if len(sels) == 0 {
+		return value, nil
+	}
+
+	switch sel := sels[0].(type) {
+	case string:
+		if obj == nil {
+			obj = map[string]interface{}{}
+		}
+
+		objv, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot set key %q of %v (%T)", sel, obj, obj)
+		}
+
+		v, err := Set(objv[sel], value, sels[1:]...)
+		if err != nil {
+			return nil, inPath(err, sel)
+		}
+
+		objv[sel] = v
+		return objv, nil
+
+	case int:
+		objv, ok := obj.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot set index %d of %v (%T)", sel, obj, obj)
+		}
+
+		idx := sel
+		if idx < 0 {
+			idx += len(objv)
+		}
+
+		if idx == len(objv) {
+			v, err := Set(nil, value, sels[1:]...)
+			if err != nil {
+				return nil, inPath(err, idx)
+			}
+
+			return append(objv, v), nil
+		}
+
+		if idx < 0 || idx > len(objv) {
+			return nil, ErrKeyNotPresent{Key: []int{sel, len(objv)}}
+		}
+
+		v, err := Set(objv[idx], value, sels[1:]...)
+		if err != nil {
+			return nil, inPath(err, idx)
+		}
+
+		objv[idx] = v
+
+		return objv, nil
+
+	default:
+		return nil, fmt.Errorf("cannot set using selector %v (%T)", sels[0], sels[0])
+	}
+}
+
+// Delete returns a Selecter with the selection removed, see the Delete
+// function.
+func (j Selecter) Delete(sels ...interface{}) (Selecter, error) {
+	v, err := Delete(j.V, sels...)
+	if err != nil {
+		return j, err
+	}
+
+	return j.wrap(v), nil
+}
+
+// Delete removes the value selected by sels from obj and returns the updated
+// obj. The selectors leading up to the last one follow the same rules as
+// Set, except that missing values are not created. The last selector may be
+// one of the following:
+//		string - delete a key from a map[string]interface{}, the map is
+//			updated in place
+//		int - delete an element from a []interface{}
+//		[]int - delete the range of elements, as selected by Select, from a
+//			[]interface{}
+// Deleting from an array returns a new array (which replaces the old one in
+// its parent), so the original array is left untouched. ErrKeyNotPresent is
+// returned if the key or index to delete does not exist.
+func Delete(obj interface{}, sels ...interface{}) (interface{}, error) {
+	if len(sels) == 0 {
+		return nil, fmt.Errorf("cannot delete without a selector")
+	}
+
+	if len(sels) == 1 {
+		return deleteLast(obj, sels[0])
+	}
+
+	switch sel := sels[0].(type) {
+	case string:
+		objv, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot select key %q of %v (%T)", sel, obj, obj)
+		}
+
+		v, ok := objv[sel]
+		if !ok {
+			return nil, ErrKeyNotPresent{Key: sel}
+		}
+
+		v, err := Delete(v, sels[1:]...)
+		if err != nil {
+			return nil, inPath(err, sel)
+		}
+
+		objv[sel] = v
+
+		return objv, nil
+
+	case int:
+		objv, ok := obj.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot select index %d of %v (%T)", sel, obj, obj)
+		}
+
+		idx := sel
+		if idx < 0 {
+			idx += len(objv)
+		}
+
+		if idx < 0 || idx >= len(objv) {
+			return nil, ErrKeyNotPresent{Key: []int{sel, len(objv)}}
+		}
+
+		v, err := Delete(objv[idx], sels[1:]...)
+		if err != nil {
+			return nil, inPath(err, idx)
+		}
+
+		objv[idx] = v
+
+		return objv, nil
+
+	default:
+		return nil, fmt.Errorf("cannot delete using selector %v (%T)", sels[0], sels[0])
+	}
+}
+
+func deleteLast(obj interface{}, sel interface{}) (interface{}, error) {
+	switch sel := sel.(type) {
+	case string:
+		objv, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot delete key %q of %v (%T)", sel, obj, obj)
+		}
+
+		if _, ok := objv[sel]; !ok {
+			return nil, ErrKeyNotPresent{Key: sel}
+		}
+
+		delete(objv, sel)
+		return objv, nil
+
+	case int:
+		objv, ok := obj.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot delete index %d of %v (%T)", sel, obj, obj)
+		}
+
+		idx := sel
+		if idx < 0 {
+			idx += len(objv)
+		}
+
+		if idx < 0 || idx >= len(objv) {
+			return nil, ErrKeyNotPresent{Key: []int{sel, len(objv)}}
+		}
+
+		return deleteRange(objv, idx, idx+1, 1), nil
+
+	case []int:
+		objv, ok := obj.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot delete range %v of %v (%T)", sel, obj, obj)
+		}
+
+		start, end, step, err := sliceBounds(sel, len(objv))
+		if err != nil {
+			return nil, err
+		}
+
+		return deleteRange(objv, start, end, step), nil
+
+	default:
+		return nil, fmt.Errorf("cannot delete using selector %v (%T)", sel, sel)
+	}
+}
+
+// deleteRange returns a new array with the elements selected by bounds from
+// sliceBounds removed
+func deleteRange(obj []interface{}, start, end, step int) []interface{} {
+	ret := make([]interface{}, 0, len(obj)-sliceLen(start, end, step))
+	for i, v := range obj {
+		if i >= start && i < end {
+			// a negative step selects starting from the end of the range
+			off := i - start
+			if step < 0 {
+				off = end - 1 - i
+				if (off % -step) == 0 {
+					continue
+				}
+			} else if (off % step) == 0 {
+				continue
+			}
+		}
+
+		ret = append(ret, v)
+	}
+
+	return ret
+}
+
+// Clone returns a deep copy of the wrapped value, so that it can be
+// modified with Set or Delete without affecting the original. Every
+// map[string]interface{}, *OrderedMap, and []interface{} is copied, the JSON
+// scalar types (string, float64, bool, and nil) are immutable. Values of any
+// other type are copied by assignment, so for example a []byte or a pointer
+// in the copy is shared with the original.
+func (j Selecter) Clone() Selecter {
+	return j.wrap(clone(j.V))
+}
+
+func clone(obj interface{}) interface{} {
+	switch objv := obj.(type) {
+	case map[string]interface{}:
+		if objv == nil {
+			return objv
+		}
+
+		ret := make(map[string]interface{}, len(objv))
+		for k, v := range objv {
+			ret[k] = clone(v)
+		}
+
+		return ret
+
+	case []interface{}:
+		if objv == nil {
+			return objv
+		}
+
+		ret := make([]interface{}, len(objv))
+		for i, v := range objv {
+			ret[i] = clone(v)
+		}
+
+		return ret
+
+	case *OrderedMap:
+		if objv == nil {
+			return objv
+		}
+
+		ret := NewOrderedMap()
+		for _, k := range objv.keys {
+			ret.Set(k, clone(objv.values[k]))
+		}
+
+		return ret
+
+	default:
+		return obj
+	}
+}
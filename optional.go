@@ -0,0 +1,96 @@
+package json_select
+
+// Optional is the result of a chain of selections started with Selecter.Opt.
+// Once a selection in the chain fails, the rest of the chain does nothing and
+// the first error is returned by the method which ends the chain, such as
+// String or Int. This saves checking an error after each step of a deep
+// selection:
+//		name, err := j.Opt("menu").Opt(0).Opt("name").String()
+type Optional struct {
+	root Selecter
+	sels []interface{}
+	cur  Selecter
+	err  error
+}
+
+// Opt starts a chain of selections with the selection of sels from j, see
+// Optional.
+func (j Selecter) Opt(sels ...interface{}) Optional {
+	return Optional{root: j, cur: j}.Opt(sels...)
+}
+
+// Opt selects sels from the current selection, unless a previous selection
+// failed.
+func (o Optional) Opt(sels ...interface{}) Optional {
+	if o.err != nil {
+		return o
+	}
+
+	next := Optional{
+		root: o.root,
+		sels: append(o.sels[:len(o.sels):len(o.sels)], sels...),
+	}
+
+	var err error
+	next.cur, err = o.cur.Select(sels...)
+	if err != nil {
+		// redo the whole selection so that the error has the same path
+		// as if the selectors had been passed to a single Select
+		_, next.err = o.root.Select(next.sels...)
+		if next.err == nil {
+			next.err = err
+		}
+	}
+
+	return next
+}
+
+// Err returns the error of the first failed selection of the chain, if any
+func (o Optional) Err() error {
+	return o.err
+}
+
+// Selecter returns the selection at the end of the chain
+func (o Optional) Selecter() (Selecter, error) {
+	if o.err != nil {
+		return Selecter{}, o.err
+	}
+
+	return o.cur, nil
+}
+
+// String returns the selection at the end of the chain as with SelectString
+func (o Optional) String() (string, error) {
+	if o.err != nil {
+		return "", o.err
+	}
+
+	return o.cur.SelectString()
+}
+
+// Int returns the selection at the end of the chain as with SelectInt
+func (o Optional) Int() (int, error) {
+	if o.err != nil {
+		return 0, o.err
+	}
+
+	return o.cur.SelectInt()
+}
+
+// Float returns the selection at the end of the chain as with SelectFloat
+func (o Optional) Float() (float64, error) {
+	if o.err != nil {
+		return 0, o.err
+	}
+
+	return o.cur.SelectFloat()
+}
+
+// Bool returns the selection at the end of the chain as with SelectBool
+func (o Optional) Bool() (bool, error) {
+	if o.err != nil {
+		return false, o.err
+	}
+
+	return o.cur.SelectBool()
+}
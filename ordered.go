@@ -0,0 +1,313 @@
+package json_select
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// OrderedMap is a JSON object which remembers the order of its keys. It's
+// created by DecodeOrdered in place of map[string]interface{} and is
+// supported by Select and the methods of Selecter which deal with objects;
+// Keys, Each, Walk, and the Star selector all follow the key order. The zero
+// value is an empty map ready to use.
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewOrderedMap returns an empty OrderedMap
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{}
+}
+
+// Get returns the value for key and whether it's present
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+// Set sets the value for key. A new key is added after all the existing
+// keys, an existing key keeps its position.
+func (m *OrderedMap) Set(key string, v interface{}) {
+	if m.values == nil {
+		m.values = map[string]interface{}{}
+	}
+
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+
+	m.values[key] = v
+}
+
+// Delete removes key from the map
+func (m *OrderedMap) Delete(key string) {
+	if _, ok := m.values[key]; !ok {
+		return
+	}
+
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i:i], m.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Keys returns the keys of the map in order
+func (m *OrderedMap) Keys() []string {
+	return append([]string{}, m.keys...)
+}
+
+// Len returns the number of keys in the map
+func (m *OrderedMap) Len() int {
+	return len(m.keys)
+}
+
+// MarshalJSON implements json.Marshaler, the keys are written in order
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+
+		vb, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(kb)
+		buf.WriteByte(':')
+		buf.Write(vb)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// DecodeOrdered decodes the JSON document in data like json.Unmarshal into
+// an interface{}, except that objects are decoded into an *OrderedMap which
+// keeps the order of the keys in the document. If a key is repeated the
+// last value is kept, in the position of the first, unless the
+// RejectDuplicateKeys or OnDuplicateKey options are used.
+func DecodeOrdered(data []byte, opts ...DecodeOption) (Selecter, error) {
+	return DecodeOrderedReader(bytes.NewReader(data), opts...)
+}
+
+// DecodeOrderedReader is like DecodeOrdered but reads the document from r
+func DecodeOrderedReader(r io.Reader, opts ...DecodeOption) (Selecter, error) {
+	var o decodeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	dec := json.NewDecoder(r)
+
+	v, err := o.decodeOrdered(dec, []interface{}{})
+	if err != nil {
+		return Selecter{}, err
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return Selecter{}, fmt.Errorf("invalid data after top-level value")
+	}
+
+	return Selecter{V: v}, nil
+}
+
+// DecodeOption configures DecodeOrdered and DecodeOrderedReader
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	onDuplicate func(ErrDuplicateKey) error
+}
+
+// RejectDuplicateKeys makes a key which is repeated in an object an
+// ErrDuplicateKey instead of the last value being kept. Parsers which treat
+// duplicate keys differently, e.g. keeping the first value, can be made to
+// disagree about what a document says, so documents which are checked by one
+// system and used by another should reject them.
+func RejectDuplicateKeys() DecodeOption {
+	return OnDuplicateKey(func(err ErrDuplicateKey) error {
+		return err
+	})
+}
+
+// OnDuplicateKey calls fn for every key which is repeated in an object, for
+// example to log a warning. If fn returns an error decoding stops and the
+// error is returned, otherwise the last value is kept.
+func OnDuplicateKey(fn func(ErrDuplicateKey) error) DecodeOption {
+	return func(o *decodeOptions) {
+		o.onDuplicate = fn
+	}
+}
+
+// ErrDuplicateKey is a key which is repeated in an object, see
+// RejectDuplicateKeys. Path is the path of the object from the root of the
+// document.
+type ErrDuplicateKey struct {
+	Key  string
+	Path []interface{}
+}
+
+func (err ErrDuplicateKey) Error() string {
+	msg := fmt.Sprintf("duplicate key %q in object", err.Key)
+	if len(err.Path) > 0 {
+		msg += " at path " + formatPath(err.Path)
+	}
+
+	return msg
+}
+
+func (o decodeOptions) decodeOrdered(dec *json.Decoder, path []interface{}) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		m := NewOrderedMap()
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			k := tok.(string)
+			if _, ok := m.values[k]; ok && o.onDuplicate != nil {
+				err := o.onDuplicate(ErrDuplicateKey{
+					Key:  k,
+					Path: append([]interface{}{}, path...),
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			v, err := o.decodeOrdered(dec, append(path, k))
+			if err != nil {
+				return nil, err
+			}
+
+			m.Set(k, v)
+		}
+
+		// the closing }
+		_, err := dec.Token()
+		return m, err
+
+	case json.Delim('['):
+		arr := []interface{}{}
+		for dec.More() {
+			v, err := o.decodeOrdered(dec, append(path, len(arr)))
+			if err != nil {
+				return nil, err
+			}
+
+			arr = append(arr, v)
+		}
+
+		// the closing ]
+		_, err := dec.Token()
+		return arr, err
+
+	default:
+		return tok, nil
+	}
+}
+
+// selectOrdered is selectValue for an *OrderedMap. Selectors which create a
+// new object keep the order of the keys, anything else is the same as for a
+// map[string]interface{}.
+func (o options) selectOrdered(obj *OrderedMap, sels []interface{}) (interface{}, error) {
+	switch sel := sels[0].(type) {
+	case Star:
+		return o.selectOrderedMatching(obj, matchAll, sels[1:])
+
+	case KeyMatch:
+		return o.selectOrderedMatching(obj, sel.Re.MatchString, sels[1:])
+
+	case KeyPrefix:
+		return o.selectOrderedMatching(obj, sel.match, sels[1:])
+
+	case KeySuffix:
+		return o.selectOrderedMatching(obj, sel.match, sels[1:])
+
+	case []string:
+		var errs Errors
+		ret := NewOrderedMap()
+		for _, k := range sel {
+			v, ok, err := o.key(obj.values, k)
+			if err != nil {
+				return nil, err
+			}
+
+			if !ok {
+				_, err := o.partial(&errs, o.keyNotPresent(obj.values, k))
+				if err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			v, err = o.selectValue(v, sels[1:])
+			keep, err := o.partial(&errs, inPath(err, k))
+			if err != nil {
+				return nil, err
+			}
+
+			if keep {
+				ret.Set(k, v)
+			}
+		}
+
+		return ret, errs.orNil()
+
+	default:
+		values := obj.values
+		if values == nil {
+			values = map[string]interface{}{}
+		}
+
+		return o.selectValue(values, sels)
+	}
+}
+
+// selectOrderedMatching is selectMatching for an *OrderedMap
+func (o options) selectOrderedMatching(obj *OrderedMap, match func(string) bool, sels []interface{}) (interface{}, error) {
+	var errs Errors
+	ret := NewOrderedMap()
+	for _, k := range obj.keys {
+		if !match(k) {
+			continue
+		}
+
+		v, err := o.selectValue(obj.values[k], sels)
+		keep, err := o.partial(&errs, inPath(err, k))
+		if err != nil {
+			return nil, err
+		}
+
+		if keep {
+			ret.Set(k, v)
+		}
+	}
+
+	return ret, errs.orNil()
+}
+
+func matchAll(string) bool {
+	return true
+}
@@ -0,0 +1,205 @@
+package json_select
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operation is an operation of an RFC 6902 JSON Patch, it can be decoded
+// from the JSON form of a patch with encoding/json. Path and From are JSON
+// Pointers, From is only used by OpMove and OpCopy, and Value is only used
+// by OpAdd, OpReplace, and OpTest.
+type Operation struct {
+	Op    Op          `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+// ApplyPatch returns a Selecter for the wrapped value with patch applied,
+// see the ApplyPatch function.
+func (j Selecter) ApplyPatch(patch []Operation) (Selecter, error) {
+	v, err := ApplyPatch(j.V, patch)
+	if err != nil {
+		return j, err
+	}
+
+	return j.wrap(v), nil
+}
+
+// ApplyPatch applies the operations of an RFC 6902 JSON Patch to obj, in
+// order, and returns the patched document. The operations are applied to a
+// Clone of obj, so obj is not modified, and if any of them fail the error
+// is returned for the whole patch. The paths are resolved against the
+// document as SelectPointer does and the changes are made with Set and
+// Delete, so the objects and arrays of obj must be map[string]interface{}
+// and []interface{}. OpTest compares values with the semantics of Equal.
+func ApplyPatch(obj interface{}, patch []Operation) (interface{}, error) {
+	doc := clone(obj)
+	for i, op := range patch {
+		var err error
+		doc, err = applyOperation(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return doc, nil
+}
+
+func applyOperation(doc interface{}, op Operation) (interface{}, error) {
+	switch op.Op {
+	case OpAdd:
+		return patchAdd(doc, op.Path, clone(op.Value))
+
+	case OpRemove:
+		sels, err := patchSels(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(sels) == 0 {
+			return nil, fmt.Errorf("cannot remove the whole document")
+		}
+
+		return Delete(doc, sels...)
+
+	case OpReplace:
+		sels, err := patchSels(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = Select(doc, sels...)
+		if err != nil {
+			return nil, err
+		}
+
+		return Set(doc, clone(op.Value), sels...)
+
+	case OpMove:
+		if op.From == op.Path {
+			return doc, nil
+		}
+
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move %s into itself", op.From)
+		}
+
+		from, err := patchSels(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(from) == 0 {
+			return nil, fmt.Errorf("cannot move the whole document")
+		}
+
+		v, err := Select(doc, from...)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err = Delete(doc, from...)
+		if err != nil {
+			return nil, err
+		}
+
+		return patchAdd(doc, op.Path, v)
+
+	case OpCopy:
+		from, err := patchSels(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := Select(doc, from...)
+		if err != nil {
+			return nil, err
+		}
+
+		return patchAdd(doc, op.Path, clone(v))
+
+	case OpTest:
+		sels, err := patchSels(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := Select(doc, sels...)
+		if err != nil {
+			return nil, err
+		}
+
+		if !equal(v, op.Value) {
+			return nil, fmt.Errorf("test failed, %v is not %v", v, op.Value)
+		}
+
+		return doc, nil
+
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// patchSels converts the JSON Pointer ptr into selectors for doc
+func patchSels(doc interface{}, ptr string) ([]interface{}, error) {
+	toks, err := parsePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	return options{}.pointerSels(doc, toks)
+}
+
+// patchAdd adds v to doc at ptr: a key of an object is set, and an element
+// is inserted into an array, with the index "-" appending
+func patchAdd(doc interface{}, ptr string, v interface{}) (interface{}, error) {
+	toks, err := parsePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(toks) == 0 {
+		return v, nil
+	}
+
+	parentSels, err := options{}.pointerSels(doc, toks[:len(toks)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := Select(doc, parentSels...)
+	if err != nil {
+		return nil, err
+	}
+
+	last := toks[len(toks)-1]
+	switch parent := parent.(type) {
+	case map[string]interface{}:
+		return Set(doc, v, append(parentSels, last)...)
+
+	case []interface{}:
+		idx := len(parent)
+		if last != "-" {
+			idx, err = pointerIndex(last)
+			if err != nil {
+				return nil, err
+			}
+
+			if idx > len(parent) {
+				return nil, ErrKeyNotPresent{Key: []int{idx, len(parent)}}
+			}
+		}
+
+		arr := make([]interface{}, 0, len(parent)+1)
+		arr = append(arr, parent[:idx]...)
+		arr = append(arr, v)
+		arr = append(arr, parent[idx:]...)
+
+		return Set(doc, arr, parentSels...)
+
+	default:
+		return nil, fmt.Errorf("cannot add %q to %v (%T)", last, parent, parent)
+	}
+}
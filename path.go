@@ -0,0 +1,242 @@
+package json_select
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrPathSyntax is returned by ParsePath and CompileJSONPath when the path is
+// malformed. Offset is the byte offset into Path where the problem was found.
+type ErrPathSyntax struct {
+	Path   string
+	Offset int
+	Msg    string
+}
+
+func (err ErrPathSyntax) Error() string {
+	return fmt.Sprintf("invalid path %q at offset %d: %s",
+		err.Path, err.Offset, err.Msg)
+}
+
+// ParsePath parses a dot notation path into selectors for Select. Keys are
+// separated by dots and array indices may either be written as a key or in
+// brackets, so "menu[0].name" and "menu.0.name" both parse into
+// []interface{}{"menu", 0, "name"}. Keys which contain dots, brackets, or
+// would otherwise parse as an index can be quoted inside brackets, as in
+// `["weird.key"]`. The empty path parses into no selectors.
+func ParsePath(path string) ([]interface{}, error) {
+	p := pathParser{path: path}
+	return p.parse()
+}
+
+// SelectPath is like Select but the selectors are given as a path accepted
+// by ParsePath.
+func (j Selecter) SelectPath(path string) (Selecter, error) {
+	sels, err := ParsePath(path)
+	if err != nil {
+		return j.wrap(nil), err
+	}
+
+	return j.Select(sels...)
+}
+
+type pathParser struct {
+	path string
+	pos  int
+}
+
+func (p *pathParser) errorf(off int, format string, args ...interface{}) error {
+	return ErrPathSyntax{
+		Path:   p.path,
+		Offset: off,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *pathParser) parse() ([]interface{}, error) {
+	sels := []interface{}{}
+
+	if p.path == "" {
+		return sels, nil
+	}
+
+	// a key is expected at the start of the path and after every dot
+	expectKey := true
+	for p.pos < len(p.path) {
+		switch p.path[p.pos] {
+		case '.':
+			if expectKey {
+				return nil, p.errorf(p.pos, "empty key")
+			}
+
+			p.pos++
+			expectKey = true
+
+		case '[':
+			if expectKey && p.pos != 0 {
+				return nil, p.errorf(p.pos, "empty key")
+			}
+
+			sel, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+
+			sels = append(sels, sel)
+			expectKey = false
+
+		case ']':
+			return nil, p.errorf(p.pos, "unbalanced ]")
+
+		default:
+			if !expectKey {
+				return nil, p.errorf(p.pos, "expected . or [")
+			}
+
+			sels = append(sels, p.key())
+			expectKey = false
+		}
+	}
+
+	if expectKey {
+		return nil, p.errorf(p.pos, "empty key")
+	}
+
+	return sels, nil
+}
+
+// key consumes a bare key, keys which are integers are returned as an int.
+func (p *pathParser) key() interface{} {
+	start := p.pos
+	end := strings.IndexAny(p.path[start:], ".[]")
+	if end < 0 {
+		end = len(p.path)
+	} else {
+		end += start
+	}
+
+	p.pos = end
+	key := p.path[start:end]
+
+	if i, ok := pathIndex(key); ok {
+		return i
+	}
+
+	return key
+}
+
+// pathIndex parses a key written as an index, which must be in canonical
+// decimal form, an optional - and no leading zeros, so that keys such as
+// "+1" and "01" are kept as keys.
+func pathIndex(key string) (int, bool) {
+	i, err := strconv.Atoi(key)
+	if err != nil || strconv.Itoa(i) != key {
+		return 0, false
+	}
+
+	return i, true
+}
+
+// bracket consumes a bracketed index or quoted key, starting at the [
+func (p *pathParser) bracket() (interface{}, error) {
+	open := p.pos
+	p.pos++
+
+	if p.pos < len(p.path) && (p.path[p.pos] == '"' || p.path[p.pos] == '\'') {
+		key, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.pos >= len(p.path) || p.path[p.pos] != ']' {
+			return nil, p.errorf(open, "unbalanced [")
+		}
+
+		p.pos++
+		return key, nil
+	}
+
+	end := strings.IndexByte(p.path[p.pos:], ']')
+	if end < 0 {
+		return nil, p.errorf(open, "unbalanced [")
+	}
+
+	end += p.pos
+	idx, err := strconv.Atoi(p.path[p.pos:end])
+	if err != nil {
+		return nil, p.errorf(p.pos, "index %q is not an integer",
+			p.path[p.pos:end])
+	}
+
+	p.pos = end + 1
+	return idx, nil
+}
+
+// quoted consumes a quoted key, starting at the quote. A backslash escapes
+// the following character.
+func (p *pathParser) quoted() (string, error) {
+	open := p.pos
+	quote := p.path[p.pos]
+	p.pos++
+
+	var sb strings.Builder
+	for p.pos < len(p.path) {
+		c := p.path[p.pos]
+		p.pos++
+
+		switch c {
+		case quote:
+			return sb.String(), nil
+		case '\\':
+			if p.pos >= len(p.path) {
+				return "", p.errorf(open, "unterminated quoted key")
+			}
+
+			sb.WriteByte(p.path[p.pos])
+			p.pos++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return "", p.errorf(open, "unterminated quoted key")
+}
+
+// formatPath formats selectors in the notation accepted by ParsePath, for
+// use in error messages. A Descend is written as "..key" and any other
+// selector is written in brackets with its Go representation.
+func formatPath(path []interface{}) string {
+	var sb strings.Builder
+	for i, sel := range path {
+		switch sel := sel.(type) {
+		case string:
+			if sel == "" || strings.ContainsAny(sel, ".[]\"'\\") {
+				sb.WriteString("[" + strconv.Quote(sel) + "]")
+				continue
+			}
+
+			if _, ok := pathIndex(sel); ok {
+				sb.WriteString("[" + strconv.Quote(sel) + "]")
+				continue
+			}
+
+			if i > 0 {
+				sb.WriteByte('.')
+			}
+
+			sb.WriteString(sel)
+
+		case int:
+			sb.WriteString("[" + strconv.Itoa(sel) + "]")
+
+		case Descend:
+			sb.WriteString(".." + sel.Key)
+
+		default:
+			fmt.Fprintf(&sb, "[%v]", sel)
+		}
+	}
+
+	return sb.String()
+}
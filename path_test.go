@@ -0,0 +1,47 @@
+package json_select
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePathIndexKeys(t *testing.T) {
+	for _, tc := range []struct {
+		path string
+		want []interface{}
+	}{
+		{"a.0", []interface{}{"a", 0}},
+		{"a.-1", []interface{}{"a", -1}},
+		{"a.10", []interface{}{"a", 10}},
+		{"a.+1", []interface{}{"a", "+1"}},
+		{"a.01", []interface{}{"a", "01"}},
+		{"a.-0", []interface{}{"a", "-0"}},
+		{"a.00", []interface{}{"a", "00"}},
+	} {
+		got, err := ParsePath(tc.path)
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParsePath(%q) = %#v, %v, want %#v", tc.path, got, err, tc.want)
+		}
+
+		// formatPath is the inverse of ParsePath
+		back, err := ParsePath(formatPath(got))
+		if err != nil || !reflect.DeepEqual(back, got) {
+			t.Errorf("ParsePath(formatPath(%#v)) = %#v, %v", got, back, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		path []interface{}
+		want string
+	}{
+		{[]interface{}{"a", "01"}, "a.01"},
+		{[]interface{}{"a", "+1"}, "a.+1"},
+		{[]interface{}{"a", "1"}, `a["1"]`},
+		{[]interface{}{"a", "-1"}, `a["-1"]`},
+		{[]interface{}{"a", 1}, "a[1]"},
+	} {
+		if got := formatPath(tc.path); got != tc.want {
+			t.Errorf("formatPath(%#v) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
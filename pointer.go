@@ -0,0 +1,171 @@
+package json_select
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SelectPointer is like Select but the selectors are given as an RFC 6901
+// JSON Pointer, e.g. "/menu/0/name". Tokens are unescaped ("~1" is "/" and
+// "~0" is "~") and tokens used against an array are converted into int
+// indices. The empty pointer selects the whole document.
+func (j Selecter) SelectPointer(ptr string) (Selecter, error) {
+	toks, err := parsePointer(ptr)
+	if err != nil {
+		return j.wrap(nil), err
+	}
+
+	sels, err := j.opts.pointerSels(j.V, toks)
+	if err != nil {
+		return j.wrap(nil), err
+	}
+
+	return j.Select(sels...)
+}
+
+// parsePointer splits a JSON Pointer into its unescaped reference tokens.
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("json pointer %q must start with /", ptr)
+	}
+
+	toks := strings.Split(ptr[1:], "/")
+	for i, tok := range toks {
+		// ~1 must be replaced before ~0 so "~01" becomes "~1" and not "/"
+		tok = strings.ReplaceAll(tok, "~1", "/")
+		tok = strings.ReplaceAll(tok, "~0", "~")
+		toks[i] = tok
+	}
+
+	return toks, nil
+}
+
+// pointerSels converts pointer tokens into selectors. Whether a token is an
+// array index or an object key depends on the value it's applied to, so the
+// tokens are resolved against obj as they are converted. Once a token misses
+// the remaining ones are left as strings and Select reports the error. The
+// tokens are resolved with the options o, which the selection must also
+// use, since they may change the value a token is applied to.
+func (o options) pointerSels(obj interface{}, toks []string) ([]interface{}, error) {
+	sels := make([]interface{}, len(toks))
+	cur := obj
+	for i, tok := range toks {
+		sels[i] = tok
+
+		if _, ok := cur.([]interface{}); ok {
+			idx, err := pointerIndex(tok)
+			if err != nil {
+				return nil, err
+			}
+
+			sels[i] = idx
+		}
+
+		cur, _ = o.selectValue(cur, sels[i:i+1])
+	}
+
+	return sels, nil
+}
+
+// pointerIndex parses an array index token, which must be a base 10 number
+// without leading zeros.
+func pointerIndex(tok string) (int, error) {
+	if tok == "-" {
+		return 0, fmt.Errorf("json pointer index \"-\" refers to a " +
+			"nonexistent element and cannot be selected")
+	}
+
+	if tok == "" || (len(tok) > 1 && tok[0] == '0') ||
+		strings.TrimLeft(tok, "0123456789") != "" {
+		return 0, fmt.Errorf("json pointer index %q is not a valid index", tok)
+	}
+
+	idx, err := strconv.Atoi(tok)
+	if err != nil {
+		return 0, fmt.Errorf("json pointer index %q is not a valid index", tok)
+	}
+
+	return idx, nil
+}
+
+// formatPointer is the inverse of parsePointer and pointerSels, it formats
+// a path of string keys and int indices as a JSON Pointer.
+func formatPointer(path []interface{}) string {
+	var sb strings.Builder
+	for _, sel := range path {
+		sb.WriteByte('/')
+		switch sel := sel.(type) {
+		case string:
+			sel = strings.ReplaceAll(sel, "~", "~0")
+			sel = strings.ReplaceAll(sel, "/", "~1")
+			sb.WriteString(sel)
+		case int:
+			sb.WriteString(strconv.Itoa(sel))
+		default:
+			fmt.Fprintf(&sb, "%v", sel)
+		}
+	}
+
+	return sb.String()
+}
+
+// maxRefs is how many $refs Resolve follows before giving up
+const maxRefs = 64
+
+// Resolve is like Select but if the selection is an object with a "$ref"
+// string, as used by JSON Schema and OpenAPI, the reference is followed and
+// the value it refers to is returned instead. Only references within the
+// same document are supported: a "#" followed by a JSON Pointer, which may
+// be percent-encoded as a URI fragment, e.g. "#/components/schemas/Pet". A
+// reference to another reference is followed in turn, up to a limit of 64,
+// and an error is returned for a reference which refers back to one already
+// followed. References nested inside the resolved value are left as is.
+func (j Selecter) Resolve(sels ...interface{}) (Selecter, error) {
+	cur, err := j.Select(sels...)
+	if err != nil {
+		return cur, err
+	}
+
+	seen := map[string]bool{}
+	for {
+		ref, ok := cur.Raw("$ref")
+		if !ok {
+			return cur, nil
+		}
+
+		refstr, ok := ref.(string)
+		if !ok {
+			return cur, nil
+		}
+
+		if seen[refstr] {
+			return j.wrap(nil), fmt.Errorf("cyclic $ref %q", refstr)
+		}
+
+		if len(seen) == maxRefs {
+			return j.wrap(nil), fmt.Errorf("too many nested $refs resolving %q", refstr)
+		}
+
+		seen[refstr] = true
+
+		if !strings.HasPrefix(refstr, "#") {
+			return j.wrap(nil), fmt.Errorf("cannot resolve $ref %q outside of the document", refstr)
+		}
+
+		ptr, err := url.PathUnescape(refstr[1:])
+		if err != nil {
+			return j.wrap(nil), fmt.Errorf("$ref %q: %w", refstr, err)
+		}
+
+		cur, err = j.SelectPointer(ptr)
+		if err != nil {
+			return j.wrap(nil), fmt.Errorf("$ref %q: %w", refstr, err)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package json_select
+
+import (
+	"testing"
+)
+
+func TestSelectPointerKeepsOptions(t *testing.T) {
+	j, err := FromJSON([]byte(`{"a":{"0":[null,{"b":1}]},"n":null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j = NewSelecter(j.V, NullAsMissing(), IntKeys())
+
+	v, err := j.SelectPointer("/a/0/1/b")
+	if err != nil || v.V != 1.0 {
+		t.Errorf("SelectPointer = %v, %v, want 1", v.V, err)
+	}
+
+	for _, ptr := range []string{"a", "/a/0/x", "/n"} {
+		v, err := j.SelectPointer(ptr)
+		if err == nil {
+			t.Errorf("SelectPointer(%q) = %v, want an error", ptr, v.V)
+		}
+
+		if v.opts != j.opts {
+			t.Errorf("SelectPointer(%q) dropped the options", ptr)
+		}
+	}
+
+	v, err = j.SelectPath("a[")
+	if err == nil || v.opts != j.opts {
+		t.Errorf("SelectPath = %v, %v, want an error with the options", v.V, err)
+	}
+}
@@ -0,0 +1,284 @@
+package json_select
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Query is a compiled chain of selectors, see Compile.
+type Query struct {
+	sels  []interface{}
+	steps []step
+}
+
+type stepKind int
+
+const (
+	// stepKey selects a string key from a map[string]interface{}
+	stepKey stepKind = iota
+	// stepIndex selects an int index from a []interface{}
+	stepIndex
+	// stepRest applies the rest of the selectors with Select, it's used
+	// for any selector that isn't a key or index, and for keys and indices
+	// of values other than a map[string]interface{} or []interface{}
+	stepRest
+)
+
+type step struct {
+	kind  stepKind
+	key   string
+	index int
+}
+
+// Compile validates sels and returns a Query which applies them. Selectors
+// which Select does not support are rejected immediately, as are malformed
+// []int slice selectors. Applying the Query is equivalent to calling Select
+// with sels, but string and int selectors are followed without going
+// through Select's type switch, which is faster when the same selectors are
+// used on many documents.
+func Compile(sels ...interface{}) (Query, error) {
+	q := Query{
+		sels:  append([]interface{}{}, sels...),
+		steps: make([]step, 0, len(sels)),
+	}
+
+	err := validateSelectors(q.sels)
+	if err != nil {
+		return Query{}, err
+	}
+
+	for _, sel := range q.sels {
+		switch sel := sel.(type) {
+		case string:
+			q.steps = append(q.steps, step{kind: stepKey, key: sel})
+		case int:
+			q.steps = append(q.steps, step{kind: stepIndex, index: sel})
+		default:
+			q.steps = append(q.steps, step{kind: stepRest})
+		}
+	}
+
+	return q, nil
+}
+
+// Apply selects from obj, the same as calling Select with the compiled
+// selectors.
+func (q Query) Apply(obj interface{}) (interface{}, error) {
+	cur := obj
+	for i, st := range q.steps {
+		ok := true
+		rest := st.kind == stepRest
+
+		switch st.kind {
+		case stepKey:
+			objv, isMap := cur.(map[string]interface{})
+			if isMap {
+				cur, ok = objv[st.key]
+			}
+
+			rest = !isMap
+
+		case stepIndex:
+			objv, isArr := cur.([]interface{})
+			if isArr {
+				idx := st.index
+				if idx < 0 {
+					idx += len(objv)
+				}
+
+				ok = idx >= 0 && idx < len(objv)
+				if ok {
+					cur = objv[idx]
+				}
+			}
+
+			rest = !isArr
+		}
+
+		if rest {
+			// anything other than a key or index of a plain map or
+			// array is left to Select
+			v, err := options{}.selectValue(cur, q.sels[i:])
+			for j := i - 1; j >= 0 && err != nil; j-- {
+				err = inPath(err, q.pathSel(obj, j))
+			}
+
+			return v, err
+		}
+
+		if !ok {
+			// misses are rare, so redo the selection to get the same
+			// error Select would return
+			_, err := Select(obj, q.sels[:i+1]...)
+			return nil, err
+		}
+	}
+
+	return cur, nil
+}
+
+// Select is like Apply but takes and returns a Selecter. The options of j
+// are used, in which case the selection is the same as j.Select.
+func (q Query) Select(j Selecter) (Selecter, error) {
+	if j.opts != (options{}) {
+		return j.Select(q.sels...)
+	}
+
+	v, err := q.Apply(j.V)
+	return j.wrap(v), err
+}
+
+// Nodes is like Select but returns each of the values selected by the
+// query, as JSONPath does, rather than how they're nested by the selectors.
+// Each selector which selects many values, such as a Wildcard or a Descend,
+// nests its values in an array or object, which Nodes flattens, taking the
+// values of a map[string]interface{} in sorted key order. Values which fail
+// to be selected are left out as with the PartialResults option. A value
+// which lacks a key, such as an element of a Wildcard without the key
+// selected after it, is not an error, as in JSONPath. For the other
+// failures an Errors is returned along with the other values.
+func (q Query) Nodes(j Selecter) ([]Selecter, error) {
+	o := j.opts
+	o.partialResults = true
+
+	v, err := o.selectRoot(j.V, q.sels)
+	errs, ok := err.(Errors)
+	if err != nil && !ok {
+		return nil, err
+	}
+
+	var failed Errors
+	for _, err := range errs {
+		if !errors.Is(err, ErrKeyNotPresent{}) {
+			failed = append(failed, err)
+		}
+	}
+
+	depth := 0
+	for _, sel := range q.sels {
+		switch sel.(type) {
+		case Star, Descend, Where, Values, KeyMatch, KeyPrefix, KeySuffix,
+			Projection, ClampRange, []string, []int:
+			depth++
+		}
+	}
+
+	var nodes []Selecter
+	flattenNodes(j.wrap(v), depth, &nodes)
+	return nodes, failed.orNil()
+}
+
+func flattenNodes(j Selecter, depth int, nodes *[]Selecter) {
+	if depth == 0 {
+		*nodes = append(*nodes, j)
+		return
+	}
+
+	switch v := j.V.(type) {
+	case []interface{}:
+		for _, elem := range v {
+			flattenNodes(j.wrap(elem), depth-1, nodes)
+		}
+
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			flattenNodes(j.wrap(v[k]), depth-1, nodes)
+		}
+
+	case *OrderedMap:
+		for _, k := range v.keys {
+			flattenNodes(j.wrap(v.values[k]), depth-1, nodes)
+		}
+	}
+}
+
+// pathSel returns the selector for the jth step as it would appear in the
+// path of an error from Select, which has negative indices normalized.
+func (q Query) pathSel(obj interface{}, j int) interface{} {
+	st := q.steps[j]
+	if st.kind != stepIndex || st.index >= 0 {
+		return q.sels[j]
+	}
+
+	v, _ := Select(obj, q.sels[:j]...)
+	arr, _ := v.([]interface{})
+	return st.index + len(arr)
+}
+
+// validateSelectors returns an ErrInvalidSelector for the first of sels
+// which is not supported by Select.
+func validateSelectors(sels []interface{}) error {
+	for i, sel := range sels {
+		msg := validateSelector(sel)
+		if msg != "" {
+			return ErrInvalidSelector{Selector: sel, Index: i, msg: msg}
+		}
+	}
+
+	return nil
+}
+
+// validateSelector returns why sel is not supported by Select, or an empty
+// string if it is.
+func validateSelector(sel interface{}) string {
+	switch sel := sel.(type) {
+	case string, []string, int, Star, Descend, Values, Coalesce, Unescape,
+		ClampRange, KeyPrefix, KeySuffix:
+		return ""
+	case KeyMatch:
+		if sel.Re == nil {
+			return "KeyMatch selector must have a non-nil Re"
+		}
+
+		return ""
+	case Where:
+		if sel == nil {
+			return "Where selector must not be nil"
+		}
+
+		return ""
+	case Func:
+		if sel == nil {
+			return "Func selector must not be nil"
+		}
+
+		return ""
+	case func(interface{}) (interface{}, error):
+		if sel == nil {
+			return "function selector must not be nil"
+		}
+
+		return ""
+	case []int:
+		if len(sel) > 3 {
+			return "slice selector can have a max of 3 elements"
+		}
+
+		if len(sel) == 3 && sel[2] == 0 {
+			return fmt.Sprintf("slice selector %v cannot have a step of 0", sel)
+		}
+
+		return ""
+	case float64:
+		if sel == math.Trunc(sel) {
+			return fmt.Sprintf("float64 selector %v must be an int, convert "+
+				"it with int(%v) or use the FloatIndexes option", sel, sel)
+		}
+
+		return fmt.Sprintf("float64 selector %v must be an int", sel)
+	case Projection:
+		for _, p := range sel {
+			switch p.(type) {
+			case string, int:
+			default:
+				return fmt.Sprintf("projection selector %v can only contain "+
+					"strings and ints, not %T", sel, p)
+			}
+		}
+
+		return ""
+	default:
+		return fmt.Sprintf("unsupported selector %v (%T)", sel, sel)
+	}
+}
@@ -0,0 +1,137 @@
+package json_select
+
+import (
+	"errors"
+	"testing"
+)
+
+// benchDocs is n documents of the shape {"a":{"b":[{"c":i}]}}
+func benchDocs(n int) []interface{} {
+	docs := make([]interface{}, n)
+	for i := range docs {
+		docs[i] = map[string]interface{}{
+			"a": map[string]interface{}{
+				"b": []interface{}{
+					map[string]interface{}{"c": float64(i)},
+				},
+			},
+		}
+	}
+
+	return docs
+}
+
+var benchSels = []interface{}{"a", "b", 0, "c"}
+
+func TestQueryApplyMatchesSelect(t *testing.T) {
+	q, err := Compile(benchSels...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, doc := range benchDocs(3) {
+		got, err := q.Apply(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want, err := Selecter{V: doc}.Select(benchSels...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != want.V {
+			t.Errorf("Apply = %v, Select = %v", got, want.V)
+		}
+	}
+}
+
+func BenchmarkQueryApply(b *testing.B) {
+	docs := benchDocs(10000)
+	q, err := Compile(benchSels...)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, doc := range docs {
+			_, err := q.Apply(doc)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkQuerySelect(b *testing.B) {
+	docs := benchDocs(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, doc := range docs {
+			_, err := Selecter{V: doc}.Select(benchSels...)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestNilSelectorsInvalid(t *testing.T) {
+	var fn func(interface{}) (interface{}, error)
+	sels := []interface{}{KeyMatch{}, Where(nil), Func(nil), fn}
+
+	ordered := NewOrderedMap()
+	ordered.Set("a", 1.0)
+
+	docs := []interface{}{
+		map[string]interface{}{"a": 1.0},
+		ordered,
+		[]interface{}{1.0},
+	}
+
+	for _, sel := range sels {
+		for _, doc := range docs {
+			_, err := Selecter{V: doc}.Select(sel)
+			if !errors.As(err, &ErrInvalidSelector{}) {
+				t.Errorf("Select(%T) of %T = %v, want ErrInvalidSelector",
+					sel, doc, err)
+			}
+		}
+
+		_, err := Compile("a", sel)
+		if !errors.As(err, &ErrInvalidSelector{}) {
+			t.Errorf("Compile(%T) = %v, want ErrInvalidSelector", sel, err)
+		}
+	}
+
+	j := Selecter{V: map[string]interface{}{
+		"a":   map[string]interface{}{"b": 1.0},
+		"arr": []interface{}{1.0},
+		"":    1.0,
+	}}
+
+	for _, sel := range append(sels, Projection{1.5}) {
+		for _, key := range []string{"a", "arr"} {
+			if j.Exists(key, sel) {
+				t.Errorf("Exists(%q, %T) = true", key, sel)
+			}
+
+			if _, ok := j.Get(key, sel); ok {
+				t.Errorf("Get(%q, %T) = true", key, sel)
+			}
+
+			if _, ok := j.Raw(key, sel); ok {
+				t.Errorf("Raw(%q, %T) = true", key, sel)
+			}
+		}
+	}
+
+	if j.Exists(Projection{1.5}) {
+		t.Error("Exists(Projection{1.5}) = true")
+	}
+}
@@ -0,0 +1,136 @@
+package json_select
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// selectReflect selects from obj using reflection, as documented by Select,
+// reporting false if obj is not a kind of value it can select from.
+func (o options) selectReflect(obj interface{}, sels []interface{}) (interface{}, bool, error) {
+	rv := reflect.ValueOf(obj)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, false, nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	var child reflect.Value
+	pathSel := sels[0]
+
+	switch sel := sels[0].(type) {
+	case string:
+		switch rv.Kind() {
+		case reflect.Struct:
+			child = structField(rv, sel)
+			if !child.IsValid() {
+				return nil, true, ErrKeyNotPresent{Key: sel}
+			}
+
+		case reflect.Map:
+			if rv.Type().Key().Kind() != reflect.String {
+				return nil, true, fmt.Errorf("cannot index %v with %q", rv.Type(), sel)
+			}
+
+			child = rv.MapIndex(reflect.ValueOf(sel).Convert(rv.Type().Key()))
+			if !child.IsValid() {
+				return nil, true, ErrKeyNotPresent{Key: sel}
+			}
+
+		default:
+			return nil, false, nil
+		}
+
+	case int:
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			idx := sel
+			if idx < 0 {
+				idx += rv.Len()
+			}
+
+			if idx < 0 || idx >= rv.Len() {
+				return nil, true, ErrKeyNotPresent{Key: []int{sel, rv.Len()}}
+			}
+
+			child = rv.Index(idx)
+			pathSel = idx
+
+		default:
+			return nil, false, nil
+		}
+
+	default:
+		switch rv.Kind() {
+		case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+			return nil, true, fmt.Errorf("cannot index %v with %v (%T)",
+				rv.Type(), sels[0], sels[0])
+		default:
+			return nil, false, nil
+		}
+	}
+
+	v, err := o.selectValue(child.Interface(), sels[1:])
+	return v, true, inPath(err, pathSel)
+}
+
+// structField returns the field of rv matching name, or the zero Value if
+// none does
+func structField(rv reflect.Value, name string) reflect.Value {
+	var byName []int
+
+	for _, f := range reflect.VisibleFields(rv.Type()) {
+		if !f.IsExported() {
+			continue
+		}
+
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && tag == "" {
+			// the fields of an embedded struct are promoted
+			t := f.Type
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+
+			if t.Kind() == reflect.Struct {
+				continue
+			}
+		}
+
+		switch {
+		case tag == "-":
+			continue
+		case tag == name:
+			return fieldByIndex(rv, f.Index)
+		case tag == "" && f.Name == name && byName == nil:
+			byName = f.Index
+		}
+	}
+
+	if byName == nil {
+		return reflect.Value{}
+	}
+
+	return fieldByIndex(rv, byName)
+}
+
+// fieldByIndex is like rv.FieldByIndex but returns the zero Value instead of
+// panicking when it would follow a nil embedded pointer
+func fieldByIndex(rv reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return reflect.Value{}
+			}
+
+			rv = rv.Elem()
+		}
+
+		rv = rv.Field(x)
+	}
+
+	return rv
+}
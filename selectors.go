@@ -0,0 +1,451 @@
+package json_select
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Star is a selector which selects every value of an object or every element
+// of an array. The remaining selectors are applied to each value, and
+// collected into a map[string]interface{} or []interface{} respectively.
+// Against an array it's equivalent to the []int{} selector. An error from
+// any of the values aborts the whole selection.
+type Star struct{}
+
+// Wildcard is the Star selector, e.g.
+//		Select(obj, "users", Wildcard, "name")
+var Wildcard = Star{}
+
+func (o options) selectEachValue(obj map[string]interface{}, sels []interface{}) (interface{}, error) {
+	var errs Errors
+
+	ret := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		v, err := o.selectValue(v, sels)
+		keep, err := o.partial(&errs, inPath(err, k))
+		if err != nil {
+			return nil, err
+		}
+
+		if keep {
+			ret[k] = v
+		}
+	}
+
+	return ret, errs.orNil()
+}
+
+func (o options) selectEachElem(obj []interface{}, sels []interface{}) (interface{}, error) {
+	var errs Errors
+
+	ret := make([]interface{}, 0, len(obj))
+	for i, v := range obj {
+		v, err := o.selectValue(v, sels)
+		keep, err := o.partial(&errs, inPath(err, i))
+		if err != nil {
+			return nil, err
+		}
+
+		if keep {
+			ret = append(ret, v)
+		}
+	}
+
+	return ret, errs.orNil()
+}
+
+// Descend is a selector which collects every value stored under Key in any
+// object at any depth below (and including) the current value. The values
+// are collected into a []interface{} and the remaining selectors are applied
+// to each of them.
+//
+// The traversal is depth first and pre-order: a matching value is collected
+// before the values nested inside of it. Array elements are visited in
+// order, object keys in sorted order since a map[string]interface{} does
+// not preserve the order of the document (the keys of an *OrderedMap are
+// visited in order). Values decoded by json.Unmarshal cannot be cyclic, a
+// hand built cyclic value will not terminate unless the MaxDepth option is
+// used.
+type Descend struct {
+	Key string
+}
+
+func (o options) selectDescend(obj interface{}, sel Descend, sels []interface{}) (interface{}, error) {
+	found, err := o.descend(obj, sel.Key, 0, []interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	if o.nullMissing {
+		kept := found[:0]
+		for _, v := range found {
+			if v != nil {
+				kept = append(kept, v)
+			}
+		}
+
+		found = kept
+	}
+
+	var errs Errors
+	ret := found[:0]
+	for _, v := range found {
+		v, err := o.selectValue(v, sels)
+		keep, err := o.partial(&errs, inPath(err, sel))
+		if err != nil {
+			return nil, err
+		}
+
+		if keep {
+			ret = append(ret, v)
+		}
+	}
+
+	return ret, errs.orNil()
+}
+
+func (o options) descend(obj interface{}, key string, depth int, found []interface{}) ([]interface{}, error) {
+	err := o.ctx.check()
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.checkDepth(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	switch objv := obj.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(objv) {
+			if k == key {
+				found = append(found, objv[k])
+			}
+
+			found, err = o.descend(objv[k], key, depth+1, found)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	case *OrderedMap:
+		for _, k := range objv.keys {
+			if k == key {
+				found = append(found, objv.values[k])
+			}
+
+			found, err = o.descend(objv.values[k], key, depth+1, found)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	case []interface{}:
+		for _, v := range objv {
+			found, err = o.descend(v, key, depth+1, found)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return found, nil
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// Where is a selector which filters an array, keeping only the elements for
+// which the predicate returns true. The remaining selectors are applied to
+// each of the kept elements. If no elements are kept the selection is an
+// empty []interface{}, not an error.
+//		Select(obj, "menu", Where(func(item Selecter) bool {
+//			name, _ := item.SelectString("name")
+//			return name == "Good Shake"
+//		}))
+type Where func(Selecter) bool
+
+func (o options) selectWhere(obj []interface{}, pred Where, sels []interface{}) (interface{}, error) {
+	var errs Errors
+	ret := []interface{}{}
+	for i, v := range obj {
+		if !pred(Selecter{V: v, opts: o}) {
+			continue
+		}
+
+		v, err := o.selectValue(v, sels)
+		keep, err := o.partial(&errs, inPath(err, i))
+		if err != nil {
+			return nil, err
+		}
+
+		if keep {
+			ret = append(ret, v)
+		}
+	}
+
+	return ret, errs.orNil()
+}
+
+// Values is a selector which selects the values of the listed keys from an
+// object into a []interface{}, in the same order as Keys. The remaining
+// selectors are applied to each of the values. A missing key is an error
+// unless NilIfMissing is set, in which case its value is nil and the
+// remaining selectors are not applied to it.
+//		Select(obj, Values{Keys: []string{"name", "price"}})
+type Values struct {
+	Keys         []string
+	NilIfMissing bool
+}
+
+func (o options) selectValues(obj map[string]interface{}, sel Values, sels []interface{}) (interface{}, error) {
+	ret := make([]interface{}, len(sel.Keys))
+	for i, k := range sel.Keys {
+		v, ok, err := o.key(obj, k)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			if sel.NilIfMissing {
+				continue
+			}
+
+			return nil, o.keyNotPresent(obj, k)
+		}
+
+		ret[i], err = o.selectValue(v, sels)
+		if err != nil {
+			return nil, inPath(err, k)
+		}
+	}
+
+	return ret, nil
+}
+
+// KeyMatch is a selector which filters an object to only the keys matching
+// Re. The remaining selectors are applied to each of the values. If no keys
+// match the selection is an empty map[string]interface{}, not an error.
+//		Select(obj, KeyMatch{regexp.MustCompile("^x-")})
+type KeyMatch struct {
+	Re *regexp.Regexp
+}
+
+// KeyPrefix is a selector which filters an object to only the keys starting
+// with Prefix, it's like a KeyMatch without compiling a regular expression.
+// The remaining selectors are applied to each of the values.
+//		Select(state, "resources", KeyPrefix{Prefix: "aws_"})
+type KeyPrefix struct {
+	Prefix string
+}
+
+func (sel KeyPrefix) match(k string) bool {
+	return strings.HasPrefix(k, sel.Prefix)
+}
+
+// KeySuffix is like KeyPrefix but keeps the keys ending with Suffix
+type KeySuffix struct {
+	Suffix string
+}
+
+func (sel KeySuffix) match(k string) bool {
+	return strings.HasSuffix(k, sel.Suffix)
+}
+
+func (o options) selectMatching(obj map[string]interface{}, match func(string) bool, sels []interface{}) (interface{}, error) {
+	var errs Errors
+	ret := map[string]interface{}{}
+	for k, v := range obj {
+		if !match(k) {
+			continue
+		}
+
+		v, err := o.selectValue(v, sels)
+		keep, err := o.partial(&errs, inPath(err, k))
+		if err != nil {
+			return nil, err
+		}
+
+		if keep {
+			ret[k] = v
+		}
+	}
+
+	return ret, errs.orNil()
+}
+
+// Coalesce is a selector which replaces a null value with Fallback before
+// the remaining selectors are applied, any other value is left as is. It
+// can appear in the middle of a chain, for example to treat a null "a" as
+// an empty object:
+//		Select(obj, "a", Coalesce{Fallback: map[string]interface{}{}}, "b")
+// With the NullAsMissing option a null key or element is reported as
+// missing when it's selected, before Coalesce is reached.
+type Coalesce struct {
+	Fallback interface{}
+}
+
+// Projection is a selector which selects several keys or elements at one
+// level. Against an object each element must be a string, and it's the same
+// as the []string selector: the result is an object with just the listed
+// keys. Against an array each element must be an int, negative values
+// counting from the end, and the result is an array of the listed elements
+// in the order they're listed. The remaining selectors are applied to each
+// selected value. A key or element which doesn't exist is an
+// ErrKeyNotPresent, and an int against an object is an error unless the
+// IntKeys option is used.
+type Projection []interface{}
+
+func (o options) selectProjection(obj interface{}, sel Projection, sels []interface{}) (interface{}, error) {
+	switch objv := obj.(type) {
+	case []interface{}:
+		ret := make([]interface{}, len(sel))
+		for i, p := range sel {
+			idx, ok := p.(int)
+			if !ok {
+				return nil, fmt.Errorf("cannot index array with %q", p)
+			}
+
+			v, idx, err := o.index(objv, idx)
+			if err != nil {
+				return nil, err
+			}
+
+			ret[i], err = o.selectValue(v, sels)
+			if err != nil {
+				return nil, inPath(err, idx)
+			}
+		}
+
+		return ret, nil
+
+	case map[string]interface{}, *OrderedMap:
+		keys := make([]string, len(sel))
+		for i, p := range sel {
+			switch p := p.(type) {
+			case string:
+				keys[i] = p
+			case int:
+				if !o.intKeys {
+					return nil, fmt.Errorf("cannot index object with %d", p)
+				}
+
+				keys[i] = strconv.Itoa(p)
+			}
+		}
+
+		return o.selectValue(obj, append([]interface{}{keys}, sels...))
+
+	default:
+		return nil, ErrNotIndexable{Value: obj, Selector: sel}
+	}
+}
+
+// Unescape is a selector which decodes a string holding a JSON document,
+// such as a "payload" field which was encoded twice, and applies the
+// remaining selectors to the decoded value. Any other value is left as is,
+// so a payload which was already decoded is selected from the same way. An
+// error decoding the string is returned with the path of the string.
+type Unescape struct{}
+
+func (o options) selectUnescape(obj interface{}, sels []interface{}) (interface{}, error) {
+	if str, ok := obj.(string); ok {
+		var v interface{}
+		err := json.Unmarshal([]byte(str), &v)
+		if err != nil {
+			return nil, pathError{
+				err: fmt.Errorf("cannot decode nested JSON: %w", err),
+			}
+		}
+
+		obj = v
+	}
+
+	return o.selectValue(obj, sels)
+}
+
+// ClampRange is a selector which selects the elements [Start:End] of an
+// array, like the []int{Start, End} selector, except that bounds outside of
+// the array are clamped to it instead of being an error. Negative bounds
+// count from the end of the array as for []int, and an End before Start
+// selects no elements. Against an array of 3 elements:
+//		ClampRange{Start: 5, End: 100}		// selects no elements
+//		ClampRange{Start: -10, End: 2}		// selects the first 2
+// Use math.MaxInt as the End to select to the end of the array.
+type ClampRange struct {
+	Start, End int
+}
+
+func (o options) selectClamp(obj []interface{}, sel ClampRange, sels []interface{}) (interface{}, error) {
+	clamp := func(i int) int {
+		if i < 0 {
+			i += len(obj)
+		}
+
+		if i < 0 {
+			return 0
+		}
+
+		if i > len(obj) {
+			return len(obj)
+		}
+
+		return i
+	}
+
+	start, end := clamp(sel.Start), clamp(sel.End)
+	if end < start {
+		end = start
+	}
+
+	var errs Errors
+	ret := make([]interface{}, 0, end-start)
+	for i, v := range obj[start:end] {
+		v, err := o.selectValue(v, sels)
+		keep, err := o.partial(&errs, inPath(err, start+i))
+		if err != nil {
+			return nil, err
+		}
+
+		if keep {
+			ret = append(ret, v)
+		}
+	}
+
+	return ret, errs.orNil()
+}
+
+// Func is a selector which replaces the current value with the result of
+// calling the function with it, and applies the remaining selectors to the
+// result. A plain func(interface{}) (interface{}, error) is also accepted.
+// It's for navigation which the other selectors can't express, such as
+// selecting whichever value of an object is the largest number:
+//		Select(obj, "scores", Func(func(v interface{}) (interface{}, error) {
+//			...
+//		}), "name")
+// An error from the function is returned with the path of the value it was
+// called with. Unlike the other selectors a Func can do anything, so a chain
+// containing one can no longer be understood, printed or compared by
+// looking at the selectors alone. The function must not modify the value
+// it's passed.
+type Func func(interface{}) (interface{}, error)
+
+func (o options) selectFunc(obj interface{}, fn Func, sels []interface{}) (interface{}, error) {
+	v, err := fn(obj)
+	if err != nil {
+		return nil, pathError{err: err}
+	}
+
+	return o.selectValue(v, sels)
+}
@@ -0,0 +1,120 @@
+package json_select
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Shape lists the kinds of values expected at paths in a document, it's a
+// lightweight alternative to a schema, see ValidateShape:
+//		Shape{
+//			{Path: []interface{}{"menu"}, Kind: KindArray},
+//			{Path: []interface{}{"menu", Wildcard, "price"}, Kind: KindNumber},
+//			{Path: []interface{}{"menu", Wildcard, "tags"}, Kind: KindArray,
+//				Optional: true},
+//		}
+type Shape []ShapeRule
+
+// ShapeRule is the Kind expected at Path, which may only contain string keys,
+// int indices, and Star selectors. A Star applies the rest of the rule to
+// every element of an array or value of an object, so an empty array matches
+// any rule below it. If Optional is set a missing key or element is not a
+// mismatch, but a present one must still be of Kind.
+type ShapeRule struct {
+	Path     []interface{}
+	Kind     Kind
+	Optional bool
+}
+
+// ValidateShape checks the wrapped value against every rule of spec and
+// returns an Errors with every mismatch, or nil if there are none. A missing
+// key or element is reported with an ErrKeyNotPresent, and a value of the
+// wrong kind with its path:
+//		expected number but found string at path menu[2].price
+// An error is returned without checking any rules if a path contains an
+// unsupported selector.
+func (j Selecter) ValidateShape(spec Shape) error {
+	for i, rule := range spec {
+		for _, sel := range rule.Path {
+			switch sel.(type) {
+			case string, int, Star:
+			default:
+				return fmt.Errorf("shape rule %d: unsupported selector %v (%T)",
+					i, sel, sel)
+			}
+		}
+	}
+
+	var errs Errors
+	for _, rule := range spec {
+		j.checkShape(j.V, []interface{}{}, rule.Path, rule, &errs)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// checkShape checks obj, found at path, against the remaining selectors of
+// rule and appends any mismatches to errs.
+func (j Selecter) checkShape(obj interface{}, path, sels []interface{}, rule ShapeRule, errs *Errors) {
+	if len(sels) == 0 {
+		if k := kindOf(obj); k != rule.Kind {
+			*errs = append(*errs, shapeError(path, "expected %v but found %v", rule.Kind, k))
+		}
+
+		return
+	}
+
+	if _, ok := sels[0].(Star); ok {
+		switch objv := obj.(type) {
+		case map[string]interface{}:
+			for _, k := range sortedKeys(objv) {
+				j.checkShape(objv[k], append(path, k), sels[1:], rule, errs)
+			}
+
+		case *OrderedMap:
+			for _, k := range objv.keys {
+				j.checkShape(objv.values[k], append(path, k), sels[1:], rule, errs)
+			}
+
+		case []interface{}:
+			for i, v := range objv {
+				j.checkShape(v, append(path, i), sels[1:], rule, errs)
+			}
+
+		default:
+			*errs = append(*errs, shapeError(path,
+				"expected array or object but found %v", kindOf(obj)))
+		}
+
+		return
+	}
+
+	v, err := j.opts.selectValue(obj, sels[:1])
+	if err != nil {
+		if rule.Optional && errors.Is(err, ErrKeyNotPresent{}) {
+			return
+		}
+
+		for i := len(path) - 1; i >= 0; i-- {
+			err = inPath(err, path[i])
+		}
+
+		*errs = append(*errs, err)
+		return
+	}
+
+	j.checkShape(v, append(path, sels[0]), sels[1:], rule, errs)
+}
+
+func shapeError(path []interface{}, format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	if len(path) > 0 {
+		msg += " at path " + formatPath(path)
+	}
+
+	return errors.New(msg)
+}
@@ -0,0 +1,162 @@
+package json_select
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// SelectReader is like Select but selects from the JSON document read from
+// r. Leading string and non-negative int selectors are followed through the
+// token stream of the document, skipping over the values which aren't
+// selected and stopping as soon as the selection is found, so only the
+// selected value is decoded. Once any other selector is reached (e.g. a
+// Wildcard or a negative index) the current value is decoded in full and
+// the rest of the selectors are applied with Select.
+//
+// Unlike json.Unmarshal, which keeps the last of duplicate keys in an
+// object, the first matching key is selected.
+func SelectReader(r io.Reader, sels ...interface{}) (Selecter, error) {
+	err := validateSelectors(sels)
+	if err != nil {
+		return Selecter{}, err
+	}
+
+	dec := json.NewDecoder(r)
+	v, err := selectStream(dec, sels)
+	return Selecter{V: v}, err
+}
+
+func selectStream(dec *json.Decoder, sels []interface{}) (interface{}, error) {
+	for i, sel := range sels {
+		var err error
+
+		switch sel := sel.(type) {
+		case string:
+			err = streamKey(dec, sel)
+		case int:
+			if sel < 0 {
+				return streamRest(dec, sels, i)
+			}
+
+			err = streamIndex(dec, sel)
+		default:
+			return streamRest(dec, sels, i)
+		}
+
+		if err != nil {
+			return nil, streamPath(err, sels[:i])
+		}
+	}
+
+	var v interface{}
+	err := dec.Decode(&v)
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// streamRest decodes the current value and applies sels[i:] to it
+func streamRest(dec *json.Decoder, sels []interface{}, i int) (interface{}, error) {
+	var v interface{}
+	err := dec.Decode(&v)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err = Select(v, sels[i:]...)
+	return v, streamPath(err, sels[:i])
+}
+
+// streamPath adds the path to err like inPath
+func streamPath(err error, path []interface{}) error {
+	for i := len(path) - 1; i >= 0 && err != nil; i-- {
+		err = inPath(err, path[i])
+	}
+
+	return err
+}
+
+// streamKey advances dec to the value for key in the current object
+func streamKey(dec *json.Decoder, key string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok != json.Delim('{') {
+		return streamMismatch(tok, key)
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		if tok == key {
+			return nil
+		}
+
+		err = streamSkip(dec)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ErrKeyNotPresent{Key: key}
+}
+
+// streamIndex advances dec to the element at idx in the current array
+func streamIndex(dec *json.Decoder, idx int) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok != json.Delim('[') {
+		return streamMismatch(tok, idx)
+	}
+
+	n := 0
+	for ; dec.More(); n++ {
+		if n == idx {
+			return nil
+		}
+
+		err = streamSkip(dec)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ErrKeyNotPresent{Key: []int{idx, n}}
+}
+
+// streamMismatch returns the error Select would for sel applied to the value
+// starting with tok, which is not the expected kind of value
+func streamMismatch(tok json.Token, sel interface{}) error {
+	var v interface{}
+	switch tok {
+	case json.Delim('{'):
+		v = map[string]interface{}{}
+	case json.Delim('['):
+		v = []interface{}{}
+	default:
+		// a scalar is a single token
+		v = tok
+		if n, ok := tok.(json.Number); ok {
+			v = string(n)
+		}
+	}
+
+	_, err := Select(v, sel)
+	return err
+}
+
+// streamSkip reads past the next value
+func streamSkip(dec *json.Decoder) error {
+	var raw json.RawMessage
+	return dec.Decode(&raw)
+}
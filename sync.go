@@ -0,0 +1,138 @@
+package json_select
+
+import (
+	"sync"
+)
+
+// SyncSelecter is a Selecter which is safe to use from multiple goroutines,
+// such as a shared configuration document which is occasionally reloaded.
+// Reads hold a read lock and updates hold the write lock. The selections
+// returned by its methods are deep copies, so they can be used after the
+// lock is released. The maps and arrays of the wrapped value must not be
+// accessed except through the SyncSelecter while it's in use, including
+// those of the value passed to NewSyncSelecter or Store.
+type SyncSelecter struct {
+	mu sync.RWMutex
+	j  Selecter
+}
+
+// NewSyncSelecter returns a SyncSelecter wrapping j
+func NewSyncSelecter(j Selecter) *SyncSelecter {
+	return &SyncSelecter{j: j}
+}
+
+// Load returns a deep copy of the wrapped Selecter
+func (s *SyncSelecter) Load() Selecter {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.j.Clone()
+}
+
+// Store replaces the wrapped Selecter with j
+func (s *SyncSelecter) Store(j Selecter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.j = j
+}
+
+// Read calls fn with the wrapped Selecter while holding the read lock. fn
+// must not modify the Selecter or retain any part of it once it returns.
+func (s *SyncSelecter) Read(fn func(Selecter) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return fn(s.j)
+}
+
+// Update calls fn with the wrapped Selecter while holding the write lock and
+// replaces it with the Selecter fn returns. If fn returns an error the
+// wrapped Selecter is not replaced, but any changes fn made in place remain.
+func (s *SyncSelecter) Update(fn func(Selecter) (Selecter, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, err := fn(s.j)
+	if err != nil {
+		return err
+	}
+
+	s.j = j
+	return nil
+}
+
+// Select is like Selecter.Select but returns a deep copy of the selection
+func (s *SyncSelecter) Select(sels ...interface{}) (Selecter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	v, err := s.j.Select(sels...)
+	if err != nil {
+		return v, err
+	}
+
+	return v.Clone(), nil
+}
+
+// Exists is like Selecter.Exists
+func (s *SyncSelecter) Exists(sels ...interface{}) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.j.Exists(sels...)
+}
+
+// SelectBool is like Selecter.SelectBool
+func (s *SyncSelecter) SelectBool(sels ...interface{}) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.j.SelectBool(sels...)
+}
+
+// SelectInt is like Selecter.SelectInt
+func (s *SyncSelecter) SelectInt(sels ...interface{}) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.j.SelectInt(sels...)
+}
+
+// SelectFloat is like Selecter.SelectFloat
+func (s *SyncSelecter) SelectFloat(sels ...interface{}) (float64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.j.SelectFloat(sels...)
+}
+
+// SelectString is like Selecter.SelectString
+func (s *SyncSelecter) SelectString(sels ...interface{}) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.j.SelectString(sels...)
+}
+
+// JSON is like Selecter.JSON
+func (s *SyncSelecter) JSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.j.JSON()
+}
+
+// Set is like Selecter.Set but updates the wrapped Selecter
+func (s *SyncSelecter) Set(value interface{}, sels ...interface{}) error {
+	return s.Update(func(j Selecter) (Selecter, error) {
+		return j.Set(value, sels...)
+	})
+}
+
+// Delete is like Selecter.Delete but updates the wrapped Selecter
+func (s *SyncSelecter) Delete(sels ...interface{}) error {
+	return s.Update(func(j Selecter) (Selecter, error) {
+		return j.Delete(sels...)
+	})
+}
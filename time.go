@@ -0,0 +1,81 @@
+package json_select
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// SelectTime is like Select but attempts to coerce the selection into a
+// string and parse it as an RFC 3339 timestamp. An error is returned if the
+// coercion or parsing fails.
+func (j Selecter) SelectTime(sels ...interface{}) (time.Time, error) {
+	return j.SelectTimeLayout(time.RFC3339, sels...)
+}
+
+// SelectTimeLayout is like SelectTime but parses the timestamp using layout,
+// see time.Parse.
+func (j Selecter) SelectTimeLayout(layout string, sels ...interface{}) (time.Time, error) {
+	str, err := j.SelectString(sels...)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(layout, str)
+	if err != nil {
+		return time.Time{}, coercionError(str, "time.Time", err)
+	}
+
+	return t, nil
+}
+
+// SelectUnix is like Select but attempts to coerce the selection into a
+// number (as with SelectFloat) and interprets it as seconds since the Unix
+// epoch. Fractional seconds are kept with up to nanosecond precision.
+func (j Selecter) SelectUnix(sels ...interface{}) (time.Time, error) {
+	f, err := j.SelectFloat(sels...)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(frac*1e9)), nil
+}
+
+// SelectDuration is like Select but attempts to coerce the selection into a
+// time.Duration. A string is parsed with time.ParseDuration, e.g. "1h30m",
+// and a number is interpreted as seconds, the same unit as SelectUnix, so
+// 1.5 is 1500ms. An error is returned if the coercion or parsing fails, or
+// if the number is out of the range of a time.Duration.
+func (j Selecter) SelectDuration(sels ...interface{}) (time.Duration, error) {
+	v, err := j.selectValue(sels)
+	if err != nil {
+		return 0, err
+	}
+
+	if v == nil {
+		return 0, fmt.Errorf("%w: %v", ErrNilValue, sels)
+	}
+
+	if str, ok := v.(string); ok {
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return 0, coercionError(str, "time.Duration", err)
+		}
+
+		return d, nil
+	}
+
+	f, ok := toFloat(v)
+	if !ok {
+		return 0, coercionError(v, "time.Duration", nil)
+	}
+
+	// a Duration is an int64 of nanoseconds, see SelectInt64 for the bounds
+	ns := math.Round(f * float64(time.Second))
+	if math.IsNaN(ns) || ns < math.MinInt64 || ns >= -math.MinInt64 {
+		return 0, coercionError(v, "time.Duration", nil)
+	}
+
+	return time.Duration(ns), nil
+}
@@ -0,0 +1,160 @@
+package json_select
+
+import (
+	"errors"
+	"fmt"
+)
+
+// IntVar sets *p to the selection as with SelectInt, or to def if the
+// selection is missing, null, or can't be converted. See Vars for reading
+// many values and checking for invalid ones at the end.
+func (j Selecter) IntVar(p *int, def int, sels ...interface{}) {
+	selectVar(j, p, def, func(s Selecter) (int, error) {
+		return s.SelectInt()
+	}, sels)
+}
+
+// FloatVar is like IntVar but converts the selection as with SelectFloat
+func (j Selecter) FloatVar(p *float64, def float64, sels ...interface{}) {
+	selectVar(j, p, def, func(s Selecter) (float64, error) {
+		return s.SelectFloat()
+	}, sels)
+}
+
+// StringVar is like IntVar but converts the selection as with SelectString
+func (j Selecter) StringVar(p *string, def string, sels ...interface{}) {
+	selectVar(j, p, def, func(s Selecter) (string, error) {
+		return s.SelectString()
+	}, sels)
+}
+
+// BoolVar is like IntVar but converts the selection as with SelectBool
+func (j Selecter) BoolVar(p *bool, def bool, sels ...interface{}) {
+	selectVar(j, p, def, func(s Selecter) (bool, error) {
+		return s.SelectBool()
+	}, sels)
+}
+
+// SelectIntOr is like SelectInt but returns def when the selection fails for
+// any reason, whether it's missing, null, or can't be converted, so the
+// failures don't need to be told apart. It's the value IntVar would set.
+func (j Selecter) SelectIntOr(def int, sels ...interface{}) int {
+	j.IntVar(&def, def, sels...)
+	return def
+}
+
+// SelectFloatOr is like SelectIntOr but converts the selection as with
+// SelectFloat
+func (j Selecter) SelectFloatOr(def float64, sels ...interface{}) float64 {
+	j.FloatVar(&def, def, sels...)
+	return def
+}
+
+// SelectStringOr is like SelectIntOr but converts the selection as with
+// SelectString
+func (j Selecter) SelectStringOr(def string, sels ...interface{}) string {
+	j.StringVar(&def, def, sels...)
+	return def
+}
+
+// SelectBoolOr is like SelectIntOr but converts the selection as with
+// SelectBool
+func (j Selecter) SelectBoolOr(def bool, sels ...interface{}) bool {
+	j.BoolVar(&def, def, sels...)
+	return def
+}
+
+// Vars reads many values from a Selecter with defaults, in the style of the
+// flag package, and collects the errors for values which are present but
+// invalid so that they can be checked once at the end:
+//		vars := cfg.Vars()
+//		vars.StringVar(&host, "localhost", "server", "host")
+//		vars.IntVar(&port, 8080, "server", "port")
+//		if err := vars.Err(); err != nil {
+//			...
+//		}
+// A missing or null value is not an error, the default is used, and neither
+// is a value below a null, such as "port" when "server" is null.
+type Vars struct {
+	j    Selecter
+	errs Errors
+}
+
+// Vars returns a Vars reading from j
+func (j Selecter) Vars() *Vars {
+	return &Vars{j: j}
+}
+
+// IntVar is like Selecter.IntVar but records an error if the selection is
+// present but invalid.
+func (v *Vars) IntVar(p *int, def int, sels ...interface{}) {
+	v.record(sels, selectVar(v.j, p, def, func(s Selecter) (int, error) {
+		return s.SelectInt()
+	}, sels))
+}
+
+// FloatVar is like Selecter.FloatVar but records an error if the selection
+// is present but invalid.
+func (v *Vars) FloatVar(p *float64, def float64, sels ...interface{}) {
+	v.record(sels, selectVar(v.j, p, def, func(s Selecter) (float64, error) {
+		return s.SelectFloat()
+	}, sels))
+}
+
+// StringVar is like Selecter.StringVar but records an error if the selection
+// is present but invalid.
+func (v *Vars) StringVar(p *string, def string, sels ...interface{}) {
+	v.record(sels, selectVar(v.j, p, def, func(s Selecter) (string, error) {
+		return s.SelectString()
+	}, sels))
+}
+
+// BoolVar is like Selecter.BoolVar but records an error if the selection is
+// present but invalid.
+func (v *Vars) BoolVar(p *bool, def bool, sels ...interface{}) {
+	v.record(sels, selectVar(v.j, p, def, func(s Selecter) (bool, error) {
+		return s.SelectBool()
+	}, sels))
+}
+
+// Err returns an Errors with the error of each invalid value read so far,
+// or nil if there were none.
+func (v *Vars) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+
+	return v.errs
+}
+
+func (v *Vars) record(sels []interface{}, err error) {
+	if err != nil {
+		v.errs = append(v.errs, fmt.Errorf("path %v: %w", sels, err))
+	}
+}
+
+// selectVar sets *p to the selection converted with conv, or def if the
+// selection is missing or null, including when an intermediate value is
+// null. If the selection fails for another reason, or can't be converted,
+// *p is set to def and the error is returned.
+func selectVar[T any](j Selecter, p *T, def T, conv func(Selecter) (T, error), sels []interface{}) error {
+	*p = def
+
+	v, err := j.Select(sels...)
+	if errors.Is(err, ErrKeyNotPresent{}) || errors.Is(err, ErrNullTraversal) ||
+		(err == nil && v.V == nil) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	t, err := conv(v)
+	if err != nil {
+		return err
+	}
+
+	*p = t
+	return nil
+}
@@ -0,0 +1,29 @@
+package json_select
+
+import (
+	"testing"
+)
+
+func TestVarsNullIntermediate(t *testing.T) {
+	j, err := FromJSON([]byte(`{"server":null,"db":{"port":"x"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var host string
+	var port, dbPort int
+
+	vars := j.Vars()
+	vars.StringVar(&host, "localhost", "server", "host")
+	vars.IntVar(&port, 8080, "server", "port")
+	vars.IntVar(&dbPort, 5432, "db", "port")
+
+	if host != "localhost" || port != 8080 || dbPort != 5432 {
+		t.Errorf("vars = %q, %d, %d, want the defaults", host, port, dbPort)
+	}
+
+	errs, ok := vars.Err().(Errors)
+	if !ok || len(errs) != 1 {
+		t.Errorf("Err = %v, want only the error for db.port", vars.Err())
+	}
+}